@@ -0,0 +1,143 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// PollResult is hint of job run for adaptive schedule
+type PollResult int32
+
+const (
+	// PollIdle no work was found, next run is delayed by backoff
+	PollIdle PollResult = iota
+	// PollMore work was found, next run is after min interval
+	PollMore
+	// PollError run failed, next run is delayed by backoff
+	PollError
+)
+
+type (
+	// PollJob is target background job which returns hint for next run by adaptive schedule
+	PollJob func(context.Context) (PollResult, error)
+
+	// AdaptivePolicy describes intervals between runs of adaptive schedule,
+	// interval is reset to MinInterval when run found work and is increased
+	// by Multiplier up to MaxInterval when run was idle or failed
+	AdaptivePolicy struct {
+		// MinInterval is interval after run which found work, must be positive
+		MinInterval time.Duration
+		// MaxInterval is upper limit of interval, zero is no limit
+		MaxInterval time.Duration
+		// Multiplier of interval for each idle or failed run, DefaultRetryMultiplier if not positive
+		Multiplier float64
+	}
+
+	pollKey struct{}
+
+	// pollHint is hint of single job run
+	pollHint struct {
+		result int32
+	}
+)
+
+// NewPoll returns new worker with target job which returns hint for adaptive schedule,
+// see ByAdaptive. Error of job run is passed to error handlers
+func NewPoll(job PollJob) *Worker {
+	if job == nil {
+		return NewE(nil)
+	}
+	return NewE(func(ctx context.Context) error {
+		r, err := job(ctx)
+		SetPollResult(ctx, r)
+		return err
+	})
+}
+
+// adaptive returns true if run in context is planned by adaptive schedule
+func adaptive(ctx context.Context) bool {
+	_, ok := ctx.Value(pollKey{}).(*pollHint)
+	return ok
+}
+
+// SetPollResult set hint of job run for adaptive schedule in run context,
+// job created by NewE can hint next run by it
+func SetPollResult(ctx context.Context, r PollResult) {
+	if h, ok := ctx.Value(pollKey{}).(*pollHint); ok {
+		atomic.StoreInt32(&h.result, int32(r))
+	}
+}
+
+// errAdaptiveConcurrency is configuration error of adaptive worker with concurrent runs,
+// hint of run should be known when next run is planned
+var errAdaptiveConcurrency = errors.New("adaptive schedule requires ConcurrencyWait policy")
+
+// ByAdaptive set adaptive schedule job wrapper with policy.
+// If min interval is not positive then worker configuration error is set,
+// worker concurrency policy other than ConcurrencyWait is configuration error too, see Err
+func (w *Worker) ByAdaptive(p AdaptivePolicy) *Worker {
+	if p.MinInterval <= 0 {
+		w.setErr(fmt.Errorf("adaptive schedule min interval %s is not positive", p.MinInterval))
+		return w
+	}
+	w.schedule, w.adaptive = ByAdaptive(p), true
+	return w
+}
+
+// ByAdaptive returns job wrapper func for run job with interval decided
+// by hint of previous run, e.g. poll queue every 100ms while work is found
+// and back off up to 1 minute when queue is empty. Failed run is hinted as PollError.
+// Next run is planned after previous run completed, timer is created by clock from context.
+// Runs are waited regardless of worker concurrency policy, MinInterval should be positive
+func ByAdaptive(p AdaptivePolicy) ScheduleFunc {
+	return func(ctx context.Context, j Job) Job {
+		return func(ctx context.Context) {
+			clock := clockFrom(ctx)
+			interval := p.MinInterval
+			next := clock.Now().Add(interval)
+			timer := clock.NewTimer(interval)
+			defer timer.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-timer.C():
+					h := new(pollHint)
+					j(withScheduled(context.WithValue(ctx, pollKey{}, h), next))
+
+					interval = p.next(interval, PollResult(atomic.LoadInt32(&h.result)))
+					next = clock.Now().Add(interval)
+					timer.Reset(interval)
+				}
+			}
+		}
+	}
+}
+
+// next returns interval after run with result
+func (p AdaptivePolicy) next(interval time.Duration, r PollResult) time.Duration {
+	if r == PollMore {
+		return p.MinInterval
+	}
+
+	multiplier := p.Multiplier
+	if multiplier <= 0 {
+		multiplier = DefaultRetryMultiplier
+	}
+	d := float64(interval) * multiplier
+	if p.MaxInterval > 0 && d > float64(p.MaxInterval) {
+		d = float64(p.MaxInterval)
+	}
+	if d > math.MaxInt64 {
+		d = math.MaxInt64
+	}
+	if interval = time.Duration(d); interval < p.MinInterval {
+		return p.MinInterval
+	}
+	return interval
+}
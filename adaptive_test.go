@@ -0,0 +1,129 @@
+package workers_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jenchik/workers"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestByAdaptive(t *testing.T) {
+	Convey("Given poll job with scripted results", t, func() {
+		start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		res := make(chan time.Time)
+		results := []workers.PollResult{
+			workers.PollMore, workers.PollMore, workers.PollIdle, workers.PollIdle, workers.PollIdle, workers.PollError, workers.PollMore,
+		}
+		run := 0
+		job := func(ctx context.Context) (workers.PollResult, error) {
+			info, _ := workers.RunInfoFrom(ctx)
+			select {
+			case res <- info.Scheduled:
+			case <-ctx.Done():
+			}
+
+			r := workers.PollIdle
+			if run < len(results) {
+				r = results[run]
+			}
+			run++
+			if r == workers.PollError {
+				return workers.PollIdle, errors.New("poll failed")
+			}
+			return r, nil
+		}
+
+		Convey("When run worker by adaptive schedule with manual clock", func() {
+			clock := workers.NewManualClock(start)
+			wrk := workers.
+				NewPoll(job).
+				ByAdaptive(workers.AdaptivePolicy{
+					MinInterval: 100 * time.Millisecond,
+					MaxInterval: 500 * time.Millisecond,
+				}).
+				WithClock(clock)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			Reset(cancel)
+			go wrk.Run(ctx)
+
+			Convey("interval should be reset by work and increased while idle or failed", func() {
+				for _, ms := range []time.Duration{100, 200, 300, 500, 900, 1400, 1900, 2000} {
+					expected := start.Add(ms * time.Millisecond)
+					clock.BlockUntil(1)
+					clock.Set(expected)
+
+					r, ok := readTimeWithTimeout(res, time.Second)
+					So(ok, ShouldBeTrue)
+					So(r, ShouldEqual, expected)
+				}
+			})
+		})
+	})
+
+	Convey("Given adaptive worker with invalid configuration", t, func() {
+		job := func(context.Context) (workers.PollResult, error) {
+			return workers.PollMore, nil
+		}
+		policy := workers.AdaptivePolicy{MinInterval: 100 * time.Millisecond}
+
+		Convey("zero min interval should be error", func() {
+			wrk := workers.NewPoll(job).ByAdaptive(workers.AdaptivePolicy{})
+			So(wrk.Err(), ShouldNotBeNil)
+			So(wrk.Run(context.Background()), ShouldEqual, wrk.Err())
+		})
+
+		Convey("concurrent runs should be error", func() {
+			for _, p := range []workers.ConcurrencyPolicy{
+				workers.ConcurrencyAllow, workers.ConcurrencyForbid, workers.ConcurrencyReplace,
+			} {
+				So(workers.NewPoll(job).ByAdaptive(policy).WithConcurrency(p).Err(), ShouldNotBeNil)
+				So(workers.NewPoll(job).WithConcurrency(p).ByAdaptive(policy).Err(), ShouldNotBeNil)
+			}
+			So(workers.NewPoll(job).ByAdaptive(policy).WithConcurrency(workers.ConcurrencyWait).Err(), ShouldBeNil)
+		})
+
+		Convey("concurrent runs should be allowed after schedule is replaced", func() {
+			wrk := workers.NewPoll(job).ByAdaptive(policy).ByTicker(time.Second).WithConcurrency(workers.ConcurrencyAllow)
+			So(wrk.Err(), ShouldBeNil)
+		})
+	})
+
+	Convey("Given adaptive schedule func of worker with concurrent runs", t, func() {
+		start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		res := make(chan time.Time)
+		clock := workers.NewManualClock(start)
+		wrk := workers.
+			NewPoll(func(ctx context.Context) (workers.PollResult, error) {
+				info, _ := workers.RunInfoFrom(ctx)
+				select {
+				case res <- info.Scheduled:
+				case <-ctx.Done():
+				}
+				return workers.PollMore, nil
+			}).
+			BySchedule(workers.ByAdaptive(workers.AdaptivePolicy{MinInterval: 100 * time.Millisecond})).
+			WithConcurrency(workers.ConcurrencyForbid).
+			WithClock(clock)
+		So(wrk.Err(), ShouldBeNil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		Reset(cancel)
+		go wrk.Run(ctx)
+
+		Convey("runs should be waited for hints", func() {
+			for i := 1; i <= 3; i++ {
+				expected := start.Add(time.Duration(i) * 100 * time.Millisecond)
+				clock.BlockUntil(1)
+				clock.Set(expected)
+
+				r, ok := readTimeWithTimeout(res, time.Second)
+				So(ok, ShouldBeTrue)
+				So(r, ShouldEqual, expected)
+			}
+		})
+	})
+}
@@ -0,0 +1,209 @@
+package workers
+
+import (
+	"time"
+)
+
+type (
+	// AtSchedule runs job once at time
+	AtSchedule struct {
+		Time time.Time
+	}
+
+	// afterSchedule runs job once after delay since schedule start
+	afterSchedule struct {
+		delay time.Duration
+	}
+
+	// timesSchedule runs job n times of schedule since schedule start
+	timesSchedule struct {
+		n int
+		s Schedule
+	}
+
+	// timesCounter is times schedule bound to start, it counts runs by schedule
+	timesCounter struct {
+		s    Schedule
+		left int
+		last time.Time
+	}
+
+	// untilSchedule runs job at times of schedule until deadline
+	untilSchedule struct {
+		s        Schedule
+		deadline time.Time
+	}
+
+	// binder is schedule which depends on schedule start time,
+	// bind returns schedule for start time
+	binder interface {
+		bind(start time.Time) Schedule
+	}
+
+	// counter is schedule which depends on runs, tick is called by driver
+	// for each run at time t, ran is false if run was skipped by worker
+	counter interface {
+		tick(t time.Time, ran bool)
+	}
+)
+
+// At returns schedule with single run at time t,
+// schedule has no runs if it's started after t
+func At(t time.Time) AtSchedule {
+	return AtSchedule{Time: t}
+}
+
+// Next returns run time if it's after t
+func (s AtSchedule) Next(t time.Time) time.Time {
+	if s.Time.After(t) {
+		return s.Time
+	}
+	return time.Time{}
+}
+
+// After returns schedule with single run after delay since schedule start
+func After(delay time.Duration) Schedule {
+	return afterSchedule{delay}
+}
+
+// Next returns run time after delay since t, schedule is bound to start time by driver
+func (s afterSchedule) Next(t time.Time) time.Time {
+	return t.Add(s.delay)
+}
+
+func (s afterSchedule) bind(start time.Time) Schedule {
+	return At(start.Add(s.delay))
+}
+
+// Times returns schedule with first n runs of schedule since schedule start.
+// Runs executed by worker are counted, runs skipped by worker, e.g. paused,
+// and times passed by slow job are not counted
+func Times(n int, s Schedule) Schedule {
+	return timesSchedule{n, s}
+}
+
+// Next returns first run time of schedule after t, schedule is bound to start time by driver
+func (s timesSchedule) Next(t time.Time) time.Time {
+	if s.n <= 0 {
+		return time.Time{}
+	}
+	return s.s.Next(t)
+}
+
+// bind returns schedule which counts runs since start
+func (s timesSchedule) bind(start time.Time) Schedule {
+	return &timesCounter{s: bind(s.s, start), left: s.n, last: start}
+}
+
+// Next returns next run time of schedule until n runs are executed
+func (c *timesCounter) Next(t time.Time) time.Time {
+	if c.left <= 0 {
+		return time.Time{}
+	}
+	return c.s.Next(t)
+}
+
+// tick count run at time t if it's not before pending run time of schedule,
+// run by other schedule of composed schedule is not counted
+func (c *timesCounter) tick(t time.Time, ran bool) {
+	pending := c.s.Next(c.last)
+	tick(c.s, t, ran)
+	if pending.IsZero() || pending.After(t) {
+		return
+	}
+	if ran {
+		c.left--
+	}
+	c.last = t
+}
+
+// Until returns schedule with runs of schedule until deadline inclusive
+func Until(deadline time.Time, s Schedule) Schedule {
+	return untilSchedule{s, deadline}
+}
+
+// Next returns next run time of schedule if it's not after deadline
+func (s untilSchedule) Next(t time.Time) time.Time {
+	next := s.s.Next(t)
+	if next.After(s.deadline) {
+		return time.Time{}
+	}
+	return next
+}
+
+func (s untilSchedule) bind(start time.Time) Schedule {
+	return untilSchedule{bind(s.s, start), s.deadline}
+}
+
+func (s untilSchedule) tick(t time.Time, ran bool) {
+	tick(s.s, t, ran)
+}
+
+func (u unionSchedule) bind(start time.Time) Schedule {
+	return unionSchedule(bindAll(u, start))
+}
+
+func (x intersectSchedule) bind(start time.Time) Schedule {
+	return intersectSchedule(bindAll(x, start))
+}
+
+func (e exceptSchedule) bind(start time.Time) Schedule {
+	return exceptSchedule{bind(e.s, start), bind(e.except, start)}
+}
+
+func (s jitteredSchedule) bind(start time.Time) Schedule {
+	return jitteredSchedule{bind(s.s, start), s.jitter}
+}
+
+func (s shiftSchedule) bind(start time.Time) Schedule {
+	return shiftSchedule{bind(s.s, start), s.c}
+}
+
+func (u unionSchedule) tick(t time.Time, ran bool) {
+	tickAll(u, t, ran)
+}
+
+func (x intersectSchedule) tick(t time.Time, ran bool) {
+	tickAll(x, t, ran)
+}
+
+func (e exceptSchedule) tick(t time.Time, ran bool) {
+	tick(e.s, t, ran)
+}
+
+func (s jitteredSchedule) tick(t time.Time, ran bool) {
+	tick(s.s, t, ran)
+}
+
+func (s shiftSchedule) tick(t time.Time, ran bool) {
+	tick(s.s, t, ran)
+}
+
+// bind returns schedule bound to start time
+func bind(s Schedule, start time.Time) Schedule {
+	if b, ok := s.(binder); ok {
+		return b.bind(start)
+	}
+	return s
+}
+
+func bindAll(schedules []Schedule, start time.Time) []Schedule {
+	bound := make([]Schedule, len(schedules))
+	for i, s := range schedules {
+		bound[i] = bind(s, start)
+	}
+	return bound
+}
+
+// tick pass run at time t to schedule which counts runs
+func tick(s Schedule, t time.Time, ran bool) {
+	if c, ok := s.(counter); ok {
+		c.tick(t, ran)
+	}
+}
+
+func tickAll(schedules []Schedule, t time.Time, ran bool) {
+	for _, s := range schedules {
+		tick(s, t, ran)
+	}
+}
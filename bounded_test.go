@@ -0,0 +1,169 @@
+package workers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jenchik/workers"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBoundedSchedules(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	Convey("Given bounded schedules", t, func() {
+		Convey("At should return single run time", func() {
+			So(workers.Preview(workers.At(start.Add(time.Hour)), start, 3), ShouldResemble, []time.Time{start.Add(time.Hour)})
+			So(workers.Preview(workers.At(start), start, 3), ShouldBeEmpty)
+		})
+
+		Convey("After should return single run time after delay since start", func() {
+			So(workers.Preview(workers.After(5*time.Minute), start, 3), ShouldResemble, []time.Time{start.Add(5 * time.Minute)})
+		})
+
+		Convey("Times should return first n run times", func() {
+			s := workers.Times(3, workers.Every(time.Minute))
+			So(workers.Preview(s, start.Add(30*time.Second), 5), ShouldResemble, []time.Time{
+				start.Add(time.Minute),
+				start.Add(2 * time.Minute),
+				start.Add(3 * time.Minute),
+			})
+			So(workers.Preview(workers.Times(0, workers.Every(time.Minute)), start, 5), ShouldBeEmpty)
+		})
+
+		Convey("Until should return run times until deadline", func() {
+			s := workers.Until(start.Add(2*time.Hour), workers.Every(time.Hour))
+			So(workers.Preview(s, start, 5), ShouldResemble, []time.Time{
+				start.Add(time.Hour),
+				start.Add(2 * time.Hour),
+			})
+		})
+
+		Convey("bounded schedules should be started by composed schedule", func() {
+			s := workers.Union(workers.Times(2, workers.Every(time.Hour)), workers.After(90*time.Minute))
+			So(workers.Preview(s, start, 5), ShouldResemble, []time.Time{
+				start.Add(time.Hour),
+				start.Add(90 * time.Minute),
+				start.Add(2 * time.Hour),
+			})
+		})
+	})
+
+	Convey("Given worker by schedule with 3 runs in group", t, func() {
+		res := make(chan time.Time)
+		completed := make(chan struct{})
+		clock := workers.NewManualClock(start)
+
+		wg := workers.NewGroup(context.Background())
+		Reset(func() {
+			wg.Stop()
+			wg.Wait(nil)
+		})
+		_, err := wg.Add(workers.
+			New(createWriterJob(res)).
+			WithSchedule(workers.Times(3, workers.Every(time.Second))).
+			WithComplete(func() { close(completed) }).
+			WithClock(clock))
+		So(err, ShouldBeNil)
+		wg.Run()
+
+		Convey("worker should be completed after 3 runs", func() {
+			for i := 1; i <= 3; i++ {
+				clock.BlockUntil(1)
+				clock.Add(time.Second)
+
+				r, ok := readTimeWithTimeout(res, time.Second)
+				So(ok, ShouldBeTrue)
+				So(r, ShouldEqual, start.Add(time.Duration(i)*time.Second))
+			}
+
+			select {
+			case <-completed:
+			case <-time.After(time.Second):
+				So("worker is not completed", ShouldBeEmpty)
+			}
+		})
+	})
+
+	Convey("Given worker by delay schedule with 3 runs and slow job", t, func() {
+		res := make(chan time.Time)
+		completed := make(chan struct{})
+		clock := workers.NewManualClock(start)
+
+		wrk := workers.New(func(ctx context.Context) {
+			res <- clock.Now()
+			// job takes longer than delay period
+			clock.Add(1500 * time.Millisecond)
+		}).
+			WithSchedule(workers.Times(3, workers.Delay(time.Second))).
+			WithComplete(func() { close(completed) }).
+			WithClock(clock)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go wrk.Run(ctx)
+
+		Convey("worker should be completed after 3 executed runs", func() {
+			for _, at := range []time.Duration{time.Second, 3500 * time.Millisecond, 6 * time.Second} {
+				clock.BlockUntil(1)
+				clock.Add(time.Second)
+
+				r, ok := readTimeWithTimeout(res, time.Second)
+				So(ok, ShouldBeTrue)
+				So(r, ShouldEqual, start.Add(at))
+			}
+
+			select {
+			case <-completed:
+			case <-time.After(time.Second):
+				So("worker is not completed", ShouldBeEmpty)
+			}
+		})
+	})
+
+	Convey("Given paused worker by schedule with 2 runs in group", t, func() {
+		res := make(chan time.Time)
+		completed := make(chan struct{})
+		clock := workers.NewManualClock(start)
+
+		wg := workers.NewGroup(context.Background())
+		Reset(func() {
+			wg.Stop()
+			wg.Wait(nil)
+		})
+		wrk := workers.
+			New(createWriterJob(res)).
+			WithSchedule(workers.Times(2, workers.Every(time.Second))).
+			WithComplete(func() { close(completed) }).
+			WithClock(clock)
+		handles, err := wg.Add(wrk)
+		So(err, ShouldBeNil)
+		handles[0].Pause()
+		wg.Run()
+
+		Convey("skipped runs should not be counted", func() {
+			clock.BlockUntil(1)
+			clock.Add(time.Second)
+			clock.BlockUntil(1)
+			So(wrk.Stats().Skipped, ShouldEqual, 1)
+
+			handles[0].Resume()
+			for i := 2; i <= 3; i++ {
+				clock.Add(time.Second)
+
+				r, ok := readTimeWithTimeout(res, time.Second)
+				So(ok, ShouldBeTrue)
+				So(r, ShouldEqual, start.Add(time.Duration(i)*time.Second))
+				if i < 3 {
+					clock.BlockUntil(1)
+				}
+			}
+
+			select {
+			case <-completed:
+			case <-time.After(time.Second):
+				So("worker is not completed", ShouldBeEmpty)
+			}
+		})
+	})
+}
@@ -0,0 +1,335 @@
+package workers
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type (
+	// Calendar is set of blackout dates and date ranges, e.g. exchange holidays.
+	// Calendar is continuous schedule of whole blackout days, so it can be
+	// used as excluded schedule by Except
+	Calendar struct {
+		loc    *time.Location
+		ranges []dateRange
+	}
+
+	// dateRange is inclusive range of dates, dates are stored as UTC midnight
+	dateRange struct {
+		from, to time.Time
+	}
+
+	// shiftSchedule moves runs of blackout dates to next allowed date
+	shiftSchedule struct {
+		s Schedule
+		c *Calendar
+	}
+)
+
+const (
+	calendarDateLayout = "2006-01-02"
+	icalDateLayout     = "20060102"
+	icalTimeLayout     = "20060102T150405"
+)
+
+// NewCalendar returns new calendar with blackout dates,
+// date is taken from year, month and day of each time as is
+func NewCalendar(dates ...time.Time) *Calendar {
+	c := &Calendar{}
+	for _, d := range dates {
+		c.AddDate(d)
+	}
+	return c
+}
+
+// AddDate add blackout date
+func (c *Calendar) AddDate(d time.Time) *Calendar {
+	return c.AddRange(d, d)
+}
+
+// AddRange add blackout dates from date to date inclusive
+func (c *Calendar) AddRange(from, to time.Time) *Calendar {
+	from, to = date(from), date(to)
+	if to.Before(from) {
+		from, to = to, from
+	}
+	c.ranges = append(c.ranges, dateRange{from, to})
+	return c
+}
+
+// In set location for check dates of times, by default location of checked time is used
+func (c *Calendar) In(loc *time.Location) *Calendar {
+	c.loc = loc
+	return c
+}
+
+// Contains returns true if date of t is blackout date
+func (c *Calendar) Contains(t time.Time) bool {
+	if c.loc != nil {
+		t = t.In(c.loc)
+	}
+	d := date(t)
+	for _, r := range c.ranges {
+		if !d.Before(r.from) && !d.After(r.to) {
+			return true
+		}
+	}
+	return false
+}
+
+// Next returns t plus nanosecond inside blackout date or start of next blackout date
+func (c *Calendar) Next(t time.Time) time.Time {
+	if c.loc != nil {
+		t = t.In(c.loc)
+	}
+	if next := t.Add(time.Nanosecond); c.Contains(next) {
+		return next
+	}
+
+	var next time.Time
+	for _, r := range c.ranges {
+		start := time.Date(r.from.Year(), r.from.Month(), r.from.Day(), 0, 0, 0, 0, t.Location())
+		if start.After(t) && (next.IsZero() || start.Before(next)) {
+			next = start
+		}
+	}
+	return next
+}
+
+// end returns start of first allowed date after t
+func (c *Calendar) end(t time.Time) time.Time {
+	if c.loc != nil {
+		t = t.In(c.loc)
+	}
+	for d := date(t); ; {
+		to, ok := c.rangeEnd(d)
+		if !ok {
+			return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, t.Location())
+		}
+		d = to.AddDate(0, 0, 1)
+	}
+}
+
+// rangeEnd returns latest end of ranges which contain date d
+func (c *Calendar) rangeEnd(d time.Time) (time.Time, bool) {
+	var to time.Time
+	ok := false
+	for _, r := range c.ranges {
+		if !d.Before(r.from) && !d.After(r.to) && (!ok || r.to.After(to)) {
+			to, ok = r.to, true
+		}
+	}
+	return to, ok
+}
+
+// Skip returns schedule with times of s excluding blackout dates
+func (c *Calendar) Skip(s Schedule) Schedule {
+	return Except(s, c)
+}
+
+// Shift returns schedule with times of s, where run of blackout date is moved
+// to same time of day of next allowed date when s has runs, runs at same time are merged into one
+func (c *Calendar) Shift(s Schedule) Schedule {
+	return shiftSchedule{s, c}
+}
+
+// Next returns next time of schedule or shifted time of skipped run
+func (s shiftSchedule) Next(t time.Time) time.Time {
+	var shifted time.Time
+	for i := 0; i < composeLimit; i++ {
+		if t = s.s.Next(t); t.IsZero() {
+			return shifted
+		}
+		if !shifted.IsZero() && !t.Before(shifted) {
+			return shifted
+		}
+		if !s.c.Contains(t) {
+			return t
+		}
+		if next := s.shift(t); !next.IsZero() && (shifted.IsZero() || next.Before(shifted)) {
+			shifted = next
+		}
+	}
+	return shifted
+}
+
+// shift returns same time of day of t at next allowed date when schedule has runs,
+// zero time if schedule has no runs at allowed dates
+func (s shiftSchedule) shift(t time.Time) time.Time {
+	if s.c.loc != nil {
+		t = t.In(s.c.loc)
+	}
+	d := s.c.end(t)
+	for i := 0; i < composeLimit; i++ {
+		next := s.s.Next(d.Add(-time.Nanosecond))
+		if next.IsZero() {
+			return next
+		}
+		next = next.In(d.Location())
+		switch {
+		case date(next).Equal(date(d)):
+			return time.Date(d.Year(), d.Month(), d.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), d.Location())
+		case s.c.Contains(next):
+			d = s.c.end(next)
+		default:
+			d = time.Date(next.Year(), next.Month(), next.Day(), 0, 0, 0, 0, d.Location())
+		}
+	}
+	return time.Time{}
+}
+
+// LoadCalendar returns calendar from file, file with ".ics" extension
+// is parsed as iCal file, other files are parsed as dates list
+func LoadCalendar(path string) (*Calendar, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".ics") {
+		return ParseICal(f)
+	}
+	return ParseCalendar(f)
+}
+
+// ParseCalendar returns calendar from dates list, each line is date "2006-01-02"
+// or inclusive dates range "2006-01-02..2006-01-05", empty lines and lines starting with "#" are ignored
+func ParseCalendar(r io.Reader) (*Calendar, error) {
+	c := &Calendar{}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.SplitN(line, "..", 2)
+		from, err := time.Parse(calendarDateLayout, strings.TrimSpace(fields[0]))
+		if err != nil {
+			return nil, fmt.Errorf("parse calendar line %d: %w", n, err)
+		}
+		to := from
+		if len(fields) == 2 {
+			if to, err = time.Parse(calendarDateLayout, strings.TrimSpace(fields[1])); err != nil {
+				return nil, fmt.Errorf("parse calendar line %d: %w", n, err)
+			}
+		}
+		c.AddRange(from, to)
+	}
+	return c, scanner.Err()
+}
+
+// ParseICal returns calendar from iCal data, each VEVENT is blackout from DTSTART to DTEND.
+// Dates of event are taken as written, DTEND of all-day event is exclusive,
+// event without DTEND is single day
+func ParseICal(r io.Reader) (*Calendar, error) {
+	lines, err := icalLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Calendar{}
+	var (
+		inEvent    bool
+		start, end string
+	)
+	for _, line := range lines {
+		name, value := icalProperty(line)
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			inEvent, start, end = true, "", ""
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if !inEvent {
+				continue
+			}
+			inEvent = false
+			if err := c.addEvent(start, end); err != nil {
+				return nil, err
+			}
+		case inEvent && name == "DTSTART":
+			start = value
+		case inEvent && name == "DTEND":
+			end = value
+		}
+	}
+	return c, nil
+}
+
+// addEvent add blackout dates of iCal event
+func (c *Calendar) addEvent(start, end string) error {
+	if start == "" {
+		return fmt.Errorf("parse iCal event: DTSTART not found")
+	}
+	from, _, err := icalTime(start)
+	if err != nil {
+		return fmt.Errorf("parse iCal event DTSTART: %w", err)
+	}
+	if end == "" {
+		c.AddRange(from, from)
+		return nil
+	}
+
+	to, allDay, err := icalTime(end)
+	if err != nil {
+		return fmt.Errorf("parse iCal event DTEND: %w", err)
+	}
+	// end is exclusive
+	if allDay || to.Equal(date(to)) {
+		to = to.AddDate(0, 0, -1)
+	}
+	if to.Before(from) {
+		to = from
+	}
+	c.AddRange(from, to)
+	return nil
+}
+
+// icalLines returns unfolded content lines of iCal data
+func icalLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// icalProperty returns upper case property name without parameters and value
+func icalProperty(line string) (string, string) {
+	i := strings.Index(line, ":")
+	if i < 0 {
+		return "", ""
+	}
+	name := line[:i]
+	if j := strings.Index(name, ";"); j >= 0 {
+		name = name[:j]
+	}
+	return strings.ToUpper(strings.TrimSpace(name)), strings.TrimSpace(line[i+1:])
+}
+
+// icalTime returns date or date time of iCal value, time zone is ignored
+func icalTime(value string) (time.Time, bool, error) {
+	value = strings.TrimSuffix(value, "Z")
+	if len(value) == len(icalDateLayout) {
+		t, err := time.Parse(icalDateLayout, value)
+		return t, true, err
+	}
+	t, err := time.Parse(icalTimeLayout, value)
+	return t, false, err
+}
+
+// date returns date of t as UTC midnight
+func date(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
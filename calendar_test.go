@@ -0,0 +1,175 @@
+package workers_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jenchik/workers"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCalendar(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2024, 12, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	Convey("Given calendar parsed from dates list", t, func() {
+		c, err := workers.ParseCalendar(strings.NewReader("# holidays\n2024-12-25\n\n2024-12-30..2024-12-31\n"))
+		So(err, ShouldBeNil)
+
+		Convey("blackout dates should be contained", func() {
+			So(c.Contains(day(25).Add(15*time.Hour)), ShouldBeTrue)
+			So(c.Contains(day(26)), ShouldBeFalse)
+			So(c.Contains(day(31).Add(23*time.Hour)), ShouldBeTrue)
+		})
+
+		Convey("Skip should exclude runs at blackout dates", func() {
+			s := c.Skip(workers.Every(24 * time.Hour))
+			So(workers.Preview(s, day(24), 3), ShouldResemble, []time.Time{day(26), day(27), day(28)})
+			So(workers.Preview(s, day(29), 2), ShouldResemble, []time.Time{
+				time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+			})
+		})
+
+		Convey("Shift should move runs to next allowed date with runs of schedule", func() {
+			weekly := func(wd time.Weekday, hour int) workers.Schedule {
+				return workers.NextFunc(func(t time.Time) time.Time {
+					for t = t.Truncate(time.Hour).Add(time.Hour); t.Weekday() != wd || t.Hour() != hour; t = t.Add(time.Hour) {
+					}
+					return t
+				})
+			}
+
+			// run of monday 30 is merged with run of next monday
+			So(workers.Preview(c.Shift(weekly(time.Monday, 10)), day(20), 3), ShouldResemble, []time.Time{
+				day(23).Add(10 * time.Hour),
+				time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC),
+				time.Date(2025, 1, 13, 10, 0, 0, 0, time.UTC),
+			})
+
+			// run of monday 30 is moved to wednesday, when schedule has runs
+			s := c.Shift(workers.Union(weekly(time.Monday, 10), weekly(time.Wednesday, 15)))
+			So(workers.Preview(s, day(27), 3), ShouldResemble, []time.Time{
+				time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+				time.Date(2025, 1, 1, 15, 0, 0, 0, time.UTC),
+				time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC),
+			})
+
+			So(workers.Preview(c.Shift(workers.Every(24*time.Hour)), day(29), 2), ShouldResemble, []time.Time{
+				time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+			})
+		})
+
+		Convey("Shift should not move runs to dates without runs of schedule", func() {
+			c := workers.NewCalendar(time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC))
+			workdays := workers.Intersect(workers.Every(24*time.Hour),
+				workers.Weekdays(time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday))
+			So(workers.Preview(c.Shift(workdays), time.Date(2026, 12, 24, 0, 0, 0, 0, time.UTC), 2), ShouldResemble, []time.Time{
+				time.Date(2026, 12, 28, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 12, 29, 0, 0, 0, 0, time.UTC),
+			})
+		})
+	})
+
+	Convey("Given iCal data with all-day and timed events", t, func() {
+		ics := strings.Join([]string{
+			"BEGIN:VCALENDAR",
+			"BEGIN:VEVENT",
+			"SUMMARY:Christmas",
+			"DTSTART;VALUE=DATE:20241225",
+			"DTEND;VALUE=DATE:20241227",
+			"END:VEVENT",
+			"BEGIN:VEVENT",
+			"SUMMARY:Maintenance",
+			"DTSTART;TZID=Europe/Berlin:20241230T",
+			" 220000",
+			"DTEND;TZID=Europe/Berlin:20241231T020000",
+			"END:VEVENT",
+			"END:VCALENDAR",
+		}, "\r\n")
+
+		c, err := workers.ParseICal(strings.NewReader(ics))
+		So(err, ShouldBeNil)
+
+		Convey("event dates should be contained", func() {
+			So(c.Contains(day(24)), ShouldBeFalse)
+			So(c.Contains(day(25)), ShouldBeTrue)
+			So(c.Contains(day(26)), ShouldBeTrue)
+			So(c.Contains(day(27)), ShouldBeFalse)
+			So(c.Contains(day(30)), ShouldBeTrue)
+			So(c.Contains(day(31)), ShouldBeTrue)
+		})
+
+		Convey("event without DTSTART should be error", func() {
+			_, err := workers.ParseICal(strings.NewReader("BEGIN:VEVENT\nEND:VEVENT\n"))
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given worker by daily schedule with blackout calendar", t, func() {
+		res := make(chan time.Time)
+		clock := workers.NewManualClock(day(1))
+		wrk := workers.
+			New(createWriterJob(res)).
+			WithSchedule(workers.Every(24 * time.Hour)).
+			WithCalendar(workers.NewCalendar(day(3))).
+			WithClock(clock)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			wrk.Run(ctx)
+		}()
+
+		Convey("run at blackout date should be skipped", func() {
+			for d := 2; d <= 4; d++ {
+				clock.BlockUntil(1)
+				clock.Add(24 * time.Hour)
+				if d == 3 {
+					continue
+				}
+
+				r, ok := readTimeWithTimeout(res, time.Second)
+				So(ok, ShouldBeTrue)
+				So(r, ShouldEqual, day(d))
+			}
+
+			clock.BlockUntil(1)
+			cancel()
+			<-done
+			So(wrk.Stats(), ShouldResemble, workers.Stats{Runs: 2, Skipped: 1})
+		})
+	})
+
+	Convey("Given worker which runs immediately and started at blackout date", t, func() {
+		res := make(chan time.Time)
+		clock := workers.NewManualClock(day(3).Add(9 * time.Hour))
+		wrk := workers.
+			New(createWriterJob(res)).
+			SetImmediately(true).
+			WithSchedule(workers.Every(24 * time.Hour)).
+			WithCalendar(workers.NewCalendar(day(3))).
+			WithClock(clock)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		Reset(cancel)
+		go wrk.Run(ctx)
+
+		Convey("immediate run should be skipped", func() {
+			clock.BlockUntil(1)
+			_, ok := readTimeWithTimeout(res, 10*time.Millisecond)
+			So(ok, ShouldBeFalse)
+			So(wrk.Stats(), ShouldResemble, workers.Stats{Skipped: 1})
+
+			clock.Set(day(4))
+			r, ok := readTimeWithTimeout(res, time.Second)
+			So(ok, ShouldBeTrue)
+			So(r, ShouldEqual, day(4))
+		})
+	})
+}
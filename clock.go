@@ -0,0 +1,89 @@
+package workers
+
+import (
+	"context"
+	"time"
+)
+
+type (
+	// Clock is source of time and timers for schedules,
+	// allows to control time in tests by ManualClock
+	Clock interface {
+		Now() time.Time
+		NewTimer(d time.Duration) Timer
+		NewTicker(d time.Duration) Ticker
+		After(d time.Duration) <-chan time.Time
+	}
+
+	// Timer is single event created by Clock
+	Timer interface {
+		C() <-chan time.Time
+		Stop() bool
+		Reset(d time.Duration) bool
+	}
+
+	// Ticker is periodic events created by Clock
+	Ticker interface {
+		C() <-chan time.Time
+		Stop()
+	}
+
+	clockKey struct{}
+
+	realClock  struct{}
+	realTimer  struct{ *time.Timer }
+	realTicker struct{ *time.Ticker }
+)
+
+// RealClock is Clock implementation by time package
+var RealClock Clock = realClock{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+func (t realTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
+
+// WithClock set clock for schedule and retry delays of worker
+func (w *Worker) WithClock(c Clock) *Worker {
+	w.clock = c
+	return w
+}
+
+// WithClock set clock for all group workers, worker clock has priority.
+// Should be called before adding workers
+func (g *Group) WithClock(c Clock) *Group {
+	g.clock = c
+	return g
+}
+
+// withClock returns context with clock for schedules
+func withClock(ctx context.Context, c Clock) context.Context {
+	return context.WithValue(ctx, clockKey{}, c)
+}
+
+// clockFrom returns clock from context, RealClock by default
+func clockFrom(ctx context.Context) Clock {
+	if c, ok := ctx.Value(clockKey{}).(Clock); ok && c != nil {
+		return c
+	}
+	return RealClock
+}
@@ -0,0 +1,223 @@
+package workers
+
+import (
+	"time"
+)
+
+type (
+	// unionSchedule runs job at times of any schedule
+	unionSchedule []Schedule
+
+	// intersectSchedule runs job at times of all schedules
+	intersectSchedule []Schedule
+
+	// exceptSchedule runs job at times of schedule excluding times of other schedule
+	exceptSchedule struct {
+		s, except Schedule
+	}
+
+	// BetweenSchedule is time window of each day from Start to End time of day,
+	// window is continuous, so each moment inside window is schedule time.
+	// End before Start means window across midnight.
+	// Nil Location means location of checked time
+	BetweenSchedule struct {
+		Start, End time.Duration
+		Location   *time.Location
+	}
+
+	// WeekdaysSchedule is time window of whole days of week,
+	// window is continuous, so each moment inside window is schedule time.
+	// Nil Location means location of checked time
+	WeekdaysSchedule struct {
+		Days     []time.Weekday
+		Location *time.Location
+	}
+
+	// window is continuous schedule, end returns end of window which contains t
+	window interface {
+		end(t time.Time) time.Time
+	}
+)
+
+// composeLimit is limit of checked schedule times for find next run time of composed schedule
+const composeLimit = 100000
+
+// Union returns schedule with times of any of schedules, e.g. cron A or cron B
+func Union(schedules ...Schedule) Schedule {
+	return unionSchedule(schedules)
+}
+
+// Next returns earliest next time of schedules
+func (u unionSchedule) Next(t time.Time) time.Time {
+	var next time.Time
+	for _, s := range u {
+		n := s.Next(t)
+		if !n.IsZero() && (next.IsZero() || n.Before(next)) {
+			next = n
+		}
+	}
+	return next
+}
+
+// Intersect returns schedule with times of all schedules,
+// e.g. every 5 minutes intersected with business hours window
+func Intersect(schedules ...Schedule) Schedule {
+	return intersectSchedule(schedules)
+}
+
+// Next returns first time after t which is next time of all schedules
+func (x intersectSchedule) Next(t time.Time) time.Time {
+	if len(x) == 0 {
+		return time.Time{}
+	}
+	for i := 0; i < composeLimit; i++ {
+		var next time.Time
+		equal := true
+		for k, s := range x {
+			n := s.Next(t)
+			if n.IsZero() {
+				return n
+			}
+			if k > 0 && !n.Equal(next) {
+				equal = false
+			}
+			if n.After(next) {
+				next = n
+			}
+		}
+		if equal {
+			return next
+		}
+		// each schedule next time is not before latest time
+		t = next.Add(-time.Nanosecond)
+	}
+	return time.Time{}
+}
+
+// Except returns schedule with times of s excluding times of except schedule,
+// e.g. every day except weekends
+func Except(s, except Schedule) Schedule {
+	return exceptSchedule{s, except}
+}
+
+// Next returns next time of schedule which is not excluded
+func (e exceptSchedule) Next(t time.Time) time.Time {
+	w, isWindow := e.except.(window)
+	for i := 0; i < composeLimit; i++ {
+		t = e.s.Next(t)
+		if t.IsZero() || !contains(e.except, t) {
+			return t
+		}
+		if isWindow {
+			// skip whole excluded window
+			if end := w.end(t); end.After(t) {
+				t = end.Add(-time.Nanosecond)
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// contains returns true if t is schedule time
+func contains(s Schedule, t time.Time) bool {
+	return s.Next(t.Add(-time.Nanosecond)).Equal(t)
+}
+
+// Between returns time window of each day between start and end time of day,
+// e.g. Between(9*time.Hour, 17*time.Hour) for business hours
+func Between(start, end time.Duration) BetweenSchedule {
+	return BetweenSchedule{Start: start, End: end}
+}
+
+// Next returns t plus nanosecond inside window or next window start
+func (b BetweenSchedule) Next(t time.Time) time.Time {
+	if b.Location != nil {
+		t = t.In(b.Location)
+	}
+	if next := t.Add(time.Nanosecond); b.inside(next) {
+		return next
+	}
+
+	for day := -1; day <= 1; day++ {
+		if start := b.at(t, day, b.Start); start.After(t) {
+			return start
+		}
+	}
+	return time.Time{}
+}
+
+func (b BetweenSchedule) end(t time.Time) time.Time {
+	if b.Location != nil {
+		t = t.In(b.Location)
+	}
+	if !b.inside(t) {
+		return t
+	}
+	for day := 0; day <= 1; day++ {
+		if end := b.at(t, day, b.End); end.After(t) {
+			return end
+		}
+	}
+	return t
+}
+
+// inside returns true if time of day of t is inside window
+func (b BetweenSchedule) inside(t time.Time) bool {
+	h, m, s := t.Clock()
+	d := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute +
+		time.Duration(s)*time.Second + time.Duration(t.Nanosecond())
+	if b.Start <= b.End {
+		return d >= b.Start && d < b.End
+	}
+	return d >= b.Start || d < b.End
+}
+
+// at returns time of day d of day after date of t
+func (b BetweenSchedule) at(t time.Time, day int, d time.Duration) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day()+day,
+		int(d/time.Hour), int(d%time.Hour/time.Minute), int(d%time.Minute/time.Second),
+		int(d%time.Second), t.Location())
+}
+
+// Weekdays returns time window of whole days of week,
+// e.g. Weekdays(time.Saturday, time.Sunday) for weekends
+func Weekdays(days ...time.Weekday) WeekdaysSchedule {
+	return WeekdaysSchedule{Days: days}
+}
+
+// Next returns t plus nanosecond inside window or next window start
+func (w WeekdaysSchedule) Next(t time.Time) time.Time {
+	if w.Location != nil {
+		t = t.In(w.Location)
+	}
+	if next := t.Add(time.Nanosecond); w.inside(next) {
+		return next
+	}
+
+	for day := 1; day <= 7; day++ {
+		start := time.Date(t.Year(), t.Month(), t.Day()+day, 0, 0, 0, 0, t.Location())
+		if w.inside(start) {
+			return start
+		}
+	}
+	return time.Time{}
+}
+
+func (w WeekdaysSchedule) end(t time.Time) time.Time {
+	if w.Location != nil {
+		t = t.In(w.Location)
+	}
+	for day := 0; day < 7 && w.inside(t); day++ {
+		t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+	}
+	return t
+}
+
+func (w WeekdaysSchedule) inside(t time.Time) bool {
+	for _, day := range w.Days {
+		if t.Weekday() == day {
+			return true
+		}
+	}
+	return false
+}
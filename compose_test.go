@@ -0,0 +1,56 @@
+package workers_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenchik/workers"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestComposedSchedules(t *testing.T) {
+	friday := time.Date(2024, 1, 5, 16, 50, 0, 0, time.UTC)
+	weekdays := workers.Weekdays(time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday)
+
+	Convey("Given composed schedules", t, func() {
+		Convey("Union should return times of any schedule", func() {
+			a, err := workers.ParseCron("0 0 9 * * *", workers.CronLocation(time.UTC))
+			So(err, ShouldBeNil)
+			b, err := workers.ParseCron("0 30 18 * * *", workers.CronLocation(time.UTC))
+			So(err, ShouldBeNil)
+
+			So(workers.Preview(workers.Union(a, b), friday, 3), ShouldResemble, []time.Time{
+				time.Date(2024, 1, 5, 18, 30, 0, 0, time.UTC),
+				time.Date(2024, 1, 6, 9, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 6, 18, 30, 0, 0, time.UTC),
+			})
+		})
+
+		Convey("Intersect should return every 5 minutes during business hours", func() {
+			s := workers.Intersect(workers.Every(5*time.Minute), workers.Between(9*time.Hour, 17*time.Hour), weekdays)
+			So(workers.Preview(s, friday, 3), ShouldResemble, []time.Time{
+				time.Date(2024, 1, 5, 16, 55, 0, 0, time.UTC),
+				time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 8, 9, 5, 0, 0, time.UTC),
+			})
+		})
+
+		Convey("Except should skip excluded times", func() {
+			s := workers.Except(workers.Every(time.Hour), workers.Weekdays(time.Saturday, time.Sunday))
+			So(workers.Preview(s, friday.Add(6*time.Hour), 2), ShouldResemble, []time.Time{
+				time.Date(2024, 1, 5, 23, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC),
+			})
+
+		})
+
+		Convey("Between across midnight should return window times", func() {
+			s := workers.Intersect(workers.Every(2*time.Hour), workers.Between(22*time.Hour, 2*time.Hour))
+			So(workers.Preview(s, friday, 3), ShouldResemble, []time.Time{
+				time.Date(2024, 1, 5, 22, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 6, 22, 0, 0, 0, time.UTC),
+			})
+		})
+	})
+}
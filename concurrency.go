@@ -0,0 +1,179 @@
+package workers
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// ConcurrencyPolicy controls how scheduled runs are started
+// when previous run of job is still running
+type ConcurrencyPolicy int
+
+const (
+	// ConcurrencyWait run job synchronously, schedule waits until run is completed
+	ConcurrencyWait ConcurrencyPolicy = iota
+	// ConcurrencyAllow start run concurrently with running ones
+	ConcurrencyAllow
+	// ConcurrencyForbid skip run if previous run is still running, skipped run is counted
+	ConcurrencyForbid
+	// ConcurrencyReplace cancel running run and start new one after it was completed
+	ConcurrencyReplace
+)
+
+type (
+	// Stats is worker runs counters
+	Stats struct {
+		// Runs is count of started job runs
+		Runs uint64
+		// Failed is count of runs completed with error
+		Failed uint64
+		// Skipped is count of scheduled runs which were not started
+		Skipped uint64
+		// Replaced is count of runs canceled by ConcurrencyReplace policy
+		Replaced uint64
+	}
+
+	// dispatcher starts scheduled runs by concurrency policy
+	dispatcher struct {
+		w      *Worker
+		job    Job
+		wg     sync.WaitGroup
+		busy   int32
+		serial sync.Mutex
+		mu     sync.Mutex
+		cancel context.CancelFunc
+		last   chan struct{}
+		stop   context.CancelFunc
+		fatal  error
+	}
+)
+
+// Stats returns worker runs counters
+func (w *Worker) Stats() Stats {
+	return Stats{
+		Runs:     atomic.LoadUint64(&w.stats.Runs),
+		Failed:   atomic.LoadUint64(&w.stats.Failed),
+		Skipped:  atomic.LoadUint64(&w.stats.Skipped),
+		Replaced: atomic.LoadUint64(&w.stats.Replaced),
+	}
+}
+
+// run job by worker concurrency policy, runs of paused worker
+// and runs at blackout dates of worker calendar are skipped
+func (d *dispatcher) run(ctx context.Context) {
+	if d.w.skipped(ctx) {
+		d.skip(ctx)
+		return
+	}
+	d.dispatch(ctx)
+}
+
+// skipped returns true if run should be skipped because worker is paused
+// or run time is at blackout date of worker calendar
+func (w *Worker) skipped(ctx context.Context) bool {
+	return paused(ctx, w) || w.calendar != nil && w.calendar.Contains(scheduledFrom(ctx))
+}
+
+// dispatch job by worker concurrency policy, run by adaptive schedule is waited
+// because its hint is needed for planning of next run
+func (d *dispatcher) dispatch(ctx context.Context) {
+	policy := d.w.concurrency
+	if adaptive(ctx) {
+		policy = ConcurrencyWait
+	}
+	switch policy {
+	case ConcurrencyAllow:
+		d.start(ctx, d.job)
+	case ConcurrencyForbid:
+		if !atomic.CompareAndSwapInt32(&d.busy, 0, 1) {
+			d.skip(ctx)
+			return
+		}
+		d.start(ctx, func(ctx context.Context) {
+			defer atomic.StoreInt32(&d.busy, 0)
+			d.job(ctx)
+		})
+	case ConcurrencyReplace:
+		d.replace(ctx)
+	default:
+		// triggered run can be dispatched concurrently with scheduled one
+		d.serial.Lock()
+		defer d.serial.Unlock()
+		d.job(ctx)
+	}
+}
+
+// skip count skipped run
+func (d *dispatcher) skip(ctx context.Context) {
+	atomic.AddUint64(&d.w.stats.Skipped, 1)
+	setSkipped(ctx)
+}
+
+// replace cancel running run and start new one after it
+func (d *dispatcher) replace(ctx context.Context) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	prev := d.last
+	if d.cancel != nil {
+		d.cancel()
+		atomic.AddUint64(&d.w.stats.Replaced, 1)
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	last := make(chan struct{})
+	d.cancel, d.last = cancel, last
+
+	d.start(runCtx, func(ctx context.Context) {
+		defer close(last)
+		defer func() {
+			d.mu.Lock()
+			if d.last == last {
+				d.cancel = nil
+			}
+			d.mu.Unlock()
+			cancel()
+		}()
+
+		if prev != nil {
+			<-prev
+		}
+		if ctx.Err() == nil {
+			d.job(ctx)
+		}
+	})
+}
+
+func (d *dispatcher) start(ctx context.Context, j Job) {
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		j(ctx)
+	}()
+}
+
+// wait until all started runs are completed
+func (d *dispatcher) wait() {
+	d.wg.Wait()
+}
+
+// fail stop schedule if error of run is fatal, first fatal error is kept
+func (d *dispatcher) fail(err error) {
+	if !isFatal(err) {
+		return
+	}
+	d.mu.Lock()
+	if d.fatal == nil {
+		d.fatal = err
+	}
+	d.mu.Unlock()
+	d.stop()
+}
+
+// err returns fatal error of run
+func (d *dispatcher) err() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.fatal
+}
@@ -0,0 +1,71 @@
+package workers_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jenchik/workers"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestConcurrencyPolicy(t *testing.T) {
+	Convey("Given job blocked until release and schedule with 3 runs", t, func() {
+		var (
+			running  int32
+			canceled int32
+			started  = make(chan struct{}, 3)
+			release  = make(chan struct{})
+		)
+		job := func(ctx context.Context) {
+			atomic.AddInt32(&running, 1)
+			started <- struct{}{}
+			select {
+			case <-release:
+			case <-ctx.Done():
+				atomic.AddInt32(&canceled, 1)
+			}
+		}
+		schedule := func(ctx context.Context, j workers.Job) workers.Job {
+			return func(ctx context.Context) {
+				for i := 0; i < 3; i++ {
+					j(ctx)
+					if i == 0 {
+						<-started
+					}
+				}
+				close(release)
+			}
+		}
+
+		Convey("When run worker with allow policy", func() {
+			wrk := workers.New(job).BySchedule(schedule).WithConcurrency(workers.ConcurrencyAllow)
+			wrk.Run(context.Background())
+
+			Convey("all runs should be started", func() {
+				So(atomic.LoadInt32(&running), ShouldEqual, 3)
+				So(wrk.Stats(), ShouldResemble, workers.Stats{Runs: 3})
+			})
+		})
+
+		Convey("When run worker with forbid policy", func() {
+			wrk := workers.New(job).BySchedule(schedule).WithConcurrency(workers.ConcurrencyForbid)
+			wrk.Run(context.Background())
+
+			Convey("runs should be skipped while first run is running", func() {
+				So(atomic.LoadInt32(&running), ShouldEqual, 1)
+				So(wrk.Stats(), ShouldResemble, workers.Stats{Runs: 1, Skipped: 2})
+			})
+		})
+
+		Convey("When run worker with replace policy", func() {
+			wrk := workers.New(job).BySchedule(schedule).WithConcurrency(workers.ConcurrencyReplace)
+			wrk.Run(context.Background())
+
+			Convey("running run should be canceled by next one", func() {
+				So(atomic.LoadInt32(&canceled), ShouldBeGreaterThanOrEqualTo, 1)
+				So(wrk.Stats().Replaced, ShouldEqual, 2)
+			})
+		})
+	})
+}
@@ -0,0 +1,91 @@
+package workers
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	resultKey       struct{}
+	errorHandlerKey struct{}
+	runInfoKey      struct{}
+	scheduledKey    struct{}
+	skippedKey      struct{}
+
+	// result of single job run
+	result struct {
+		err error
+	}
+
+	// RunInfo describes single job run, available in job context by RunInfoFrom
+	RunInfo struct {
+		// Worker is worker name
+		Worker string
+		// Labels is worker labels, should not be modified
+		Labels map[string]string
+		// RunID is unique identifier of run in process
+		RunID uint64
+		// Scheduled is time of run by schedule
+		Scheduled time.Time
+		// Attempt is number of attempt of run, starting with 1
+		Attempt int
+	}
+)
+
+// lastRunID is counter of job runs for run identifiers
+var lastRunID uint64
+
+// RunInfoFrom returns descriptor of current job run from job context
+func RunInfoFrom(ctx context.Context) (RunInfo, bool) {
+	info, ok := ctx.Value(runInfoKey{}).(RunInfo)
+	return info, ok
+}
+
+// withRunInfo returns context with descriptor of new job run
+func withRunInfo(ctx context.Context, w *Worker) context.Context {
+	return context.WithValue(ctx, runInfoKey{}, RunInfo{
+		Worker:    w.name,
+		Labels:    w.labels,
+		RunID:     atomic.AddUint64(&lastRunID, 1),
+		Scheduled: scheduledFrom(ctx),
+		Attempt:   1,
+	})
+}
+
+// withAttempt returns context with run descriptor for retry attempt
+func withAttempt(ctx context.Context, attempt int) context.Context {
+	info, ok := RunInfoFrom(ctx)
+	if !ok {
+		return ctx
+	}
+	info.Attempt = attempt
+	return context.WithValue(ctx, runInfoKey{}, info)
+}
+
+// withScheduled returns context with run time by schedule
+func withScheduled(ctx context.Context, t time.Time) context.Context {
+	return context.WithValue(ctx, scheduledKey{}, t)
+}
+
+// scheduledFrom returns run time by schedule from context or current time of clock
+func scheduledFrom(ctx context.Context) time.Time {
+	if t, ok := ctx.Value(scheduledKey{}).(time.Time); ok {
+		return t
+	}
+	return clockFrom(ctx).Now()
+}
+
+// setSkipped mark scheduled run in context as skipped by worker
+func setSkipped(ctx context.Context) {
+	if skipped, ok := ctx.Value(skippedKey{}).(*int32); ok {
+		atomic.StoreInt32(skipped, 1)
+	}
+}
+
+// setResult store job run error to run result in context
+func setResult(ctx context.Context, err error) {
+	if r, ok := ctx.Value(resultKey{}).(*result); ok {
+		r.err = err
+	}
+}
@@ -0,0 +1,169 @@
+package workers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type (
+	// CronOption is option of cron schedule
+	CronOption func(*cronSchedule)
+
+	// cronSchedule is cron spec schedule in time zone
+	cronSchedule struct {
+		spec    cronSpec
+		mode    CronMode
+		loc     *time.Location
+		skipGap bool
+		jitter  []Jitter
+	}
+)
+
+// cronNextLimit is limit of checked spec times for find next run time
+const cronNextLimit = 1000
+
+// CronLocation set time zone of cron spec, time.Local by default.
+// Time zone set by spec prefix CRON_TZ=<zone> or TZ=<zone> has priority
+func CronLocation(loc *time.Location) CronOption {
+	return func(s *cronSchedule) {
+		s.loc = loc
+	}
+}
+
+// CronSkipDSTGap set skip runs which time doesn't exist because of
+// daylight saving time transition, by default such runs are executed
+// at transition time
+func CronSkipDSTGap() CronOption {
+	return func(s *cronSchedule) {
+		s.skipGap = true
+	}
+}
+
+// CronJitter add jitter of cron schedule runs, e.g. CronJitter(workers.MaxJitter(time.Minute))
+func CronJitter(j Jitter) CronOption {
+	return func(s *cronSchedule) {
+		s.jitter = append(s.jitter, j)
+	}
+}
+
+// parseCron returns cron schedule for spec with optional time zone prefix
+func parseCron(spec string, opts ...CronOption) (*cronSchedule, error) {
+	s := &cronSchedule{loc: time.Local}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "CRON_TZ=") || strings.HasPrefix(spec, "TZ=") {
+		i := strings.IndexAny(spec, " \t")
+		if i == -1 {
+			return nil, fmt.Errorf("missing fields after time zone in spec %q", spec)
+		}
+		loc, err := time.LoadLocation(spec[strings.Index(spec, "=")+1 : i])
+		if err != nil {
+			return nil, err
+		}
+		s.loc, spec = loc, strings.TrimSpace(spec[i:])
+	}
+	if s.loc == nil {
+		s.loc = time.Local
+	}
+
+	var err error
+	if s.spec, err = parseCronSpec(spec, s.mode); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Next returns next run time after t, zero time if there is no run time.
+// Spec is matched with wall clock in schedule time zone, so run by spec time
+// which is repeated on daylight saving time fall-back is executed once
+// at first occurrence, run by spec time which is skipped on spring-forward
+// is executed at transition time if skipping is not set.
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	if _, ok := s.spec.(everySpec); ok {
+		return s.spec.next(t)
+	}
+
+	wall := wallClock(t.In(s.loc))
+	for i := 0; i < cronNextLimit; i++ {
+		wall = s.spec.next(wall)
+		if wall.IsZero() {
+			return wall
+		}
+
+		next := s.earliest(wall)
+		if !wallClock(next).Equal(wall) {
+			// wall clock time doesn't exist
+			if s.skipGap {
+				continue
+			}
+			next = s.transition(next, wall)
+		}
+		if next.After(t) {
+			return next
+		}
+		// t is in second occurrence of wall clock repeated on fall-back,
+		// repeated spec times were passed at first occurrence
+		if end := s.repeatedEnd(next, t); end.After(wall) {
+			wall = end.Add(-time.Second)
+		}
+	}
+	return time.Time{}
+}
+
+// earliest returns first time in schedule time zone with wall clock
+func (s *cronSchedule) earliest(wall time.Time) time.Time {
+	t := time.Date(wall.Year(), wall.Month(), wall.Day(),
+		wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), s.loc)
+
+	// wall clock is repeated if offset was decreased before
+	_, offset := t.Zone()
+	_, prevOffset := t.Add(-3 * time.Hour).Zone()
+	if prevOffset > offset {
+		prev := t.Add(-time.Duration(prevOffset-offset) * time.Second)
+		if wallClock(prev).Equal(wallClock(t)) {
+			return prev
+		}
+	}
+	return t
+}
+
+// transition returns first time not before wall clock, t is normalized time of wall clock
+func (s *cronSchedule) transition(t, wall time.Time) time.Time {
+	lo, hi := t.Add(-3*time.Hour).Unix(), t.Unix()
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if wallClock(time.Unix(mid, 0).In(s.loc)).Before(wall) {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return time.Unix(lo, 0).In(s.loc)
+}
+
+// repeatedEnd returns first wall clock after wall clock times repeated on fall-back,
+// first is time of repeated wall clock before fall-back and t is time after fall-back
+func (s *cronSchedule) repeatedEnd(first, t time.Time) time.Time {
+	_, offset := t.In(s.loc).Zone()
+	_, prevOffset := first.Zone()
+	lo, hi := first.Unix(), t.Unix()
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if _, o := time.Unix(mid, 0).In(s.loc).Zone(); o != offset {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return wallClock(time.Unix(lo, 0).In(s.loc)).Add(time.Duration(prevOffset-offset) * time.Second)
+}
+
+// wallClock returns wall clock of t in UTC
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(),
+		t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
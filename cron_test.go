@@ -0,0 +1,210 @@
+package workers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jenchik/workers"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCronTimeZone(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("time zone database is not available:", err)
+	}
+
+	Convey("Given daily at 02:30 cron worker in Europe/Berlin time zone", t, func() {
+		res := make(chan time.Time)
+		run := func(start time.Time, opts ...workers.CronOption) (*workers.ManualClock, context.CancelFunc) {
+			clock := workers.NewManualClock(start)
+			wrk := workers.New(createWriterJob(res)).
+				ByCronSpec("0 30 2 * * *", opts...).
+				WithClock(clock)
+			So(wrk.Err(), ShouldBeNil)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			go wrk.Run(ctx)
+			clock.BlockUntil(1)
+			return clock, cancel
+		}
+
+		Convey("When time zone set by spec prefix", func() {
+			_, err := workers.ParseCronSchedule("CRON_TZ=Europe/Berlin 0 30 2 * * *")
+			So(err, ShouldBeNil)
+			_, err = workers.ParseCronSchedule("TZ=Unknown/Zone 0 30 2 * * *")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("When day of spring-forward transition", func() {
+			clock, cancel := run(time.Date(2024, 3, 30, 12, 0, 0, 0, berlin), workers.CronLocation(berlin))
+			defer cancel()
+			clock.Add(24 * time.Hour)
+
+			Convey("run should be executed at transition time", func() {
+				r, ok := readTimeWithTimeout(res, time.Second)
+				So(ok, ShouldBeTrue)
+				So(r.Equal(time.Date(2024, 3, 31, 3, 0, 0, 0, berlin)), ShouldBeTrue)
+			})
+		})
+
+		Convey("When day of spring-forward transition with skipping runs", func() {
+			clock, cancel := run(time.Date(2024, 3, 30, 12, 0, 0, 0, berlin),
+				workers.CronLocation(berlin), workers.CronSkipDSTGap())
+			defer cancel()
+			clock.Add(48 * time.Hour)
+
+			Convey("run should be executed next day", func() {
+				r, ok := readTimeWithTimeout(res, time.Second)
+				So(ok, ShouldBeTrue)
+				So(r.Equal(time.Date(2024, 4, 1, 2, 30, 0, 0, berlin)), ShouldBeTrue)
+			})
+		})
+
+		Convey("When day of fall-back transition", func() {
+			clock, cancel := run(time.Date(2024, 10, 26, 12, 0, 0, 0, time.UTC), workers.CronLocation(berlin))
+			defer cancel()
+			clock.Add(24 * time.Hour)
+
+			Convey("run should be executed once at first occurrence", func() {
+				r, ok := readTimeWithTimeout(res, time.Second)
+				So(ok, ShouldBeTrue)
+				So(r.Equal(time.Date(2024, 10, 27, 0, 30, 0, 0, time.UTC)), ShouldBeTrue)
+
+				clock.BlockUntil(1)
+				clock.Add(24 * time.Hour)
+				r, ok = readTimeWithTimeout(res, time.Second)
+				So(ok, ShouldBeTrue)
+				So(r.Equal(time.Date(2024, 10, 28, 1, 30, 0, 0, time.UTC)), ShouldBeTrue)
+			})
+		})
+
+		Convey("When sub-hour schedule is started inside repeated hour of fall-back", func() {
+			start := time.Date(2024, 10, 27, 1, 30, 0, 0, time.UTC)
+			for _, spec := range []string{"* * * * * *", "0 */10 * * * *"} {
+				s, err := workers.ParseCron(spec, workers.CronLocation(berlin))
+				So(err, ShouldBeNil)
+
+				Convey("next run should be after repeated hour for "+spec, func() {
+					So(s.Next(start).Equal(time.Date(2024, 10, 27, 3, 0, 0, 0, berlin)), ShouldBeTrue)
+				})
+			}
+		})
+	})
+}
+
+func TestCronSpecSyntax(t *testing.T) {
+	cases := []struct {
+		spec     string
+		mode     workers.CronMode
+		start    time.Time
+		expected []time.Time
+	}{
+		{
+			spec:  "0 0 18 LW * *",
+			start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2024, 1, 31, 18, 0, 0, 0, time.UTC),
+				time.Date(2024, 2, 29, 18, 0, 0, 0, time.UTC),
+				time.Date(2024, 3, 29, 18, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec:  "0 9 15W * *",
+			mode:  workers.CronStandard,
+			start: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2024, 6, 14, 9, 0, 0, 0, time.UTC),
+				time.Date(2024, 7, 15, 9, 0, 0, 0, time.UTC),
+				time.Date(2024, 8, 15, 9, 0, 0, 0, time.UTC),
+				time.Date(2024, 9, 16, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec:  "30 9 * * FRI#3",
+			mode:  workers.CronSecondsOptional,
+			start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2024, 1, 19, 9, 30, 0, 0, time.UTC),
+				time.Date(2024, 2, 16, 9, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec:  "*/20 0 0 * * 5L",
+			mode:  workers.CronSecondsOptional,
+			start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2024, 1, 26, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 26, 0, 0, 20, 0, time.UTC),
+				time.Date(2024, 1, 26, 0, 0, 40, 0, time.UTC),
+				time.Date(2024, 2, 23, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec:  "0 0 12 L-1 * ? 2025",
+			mode:  workers.CronExtended,
+			start: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2025, 1, 30, 12, 0, 0, 0, time.UTC),
+				time.Date(2025, 2, 27, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec:  "@hourly",
+			mode:  workers.CronStandard,
+			start: time.Date(2024, 1, 1, 0, 10, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	Convey("Given cron specs with extended syntax", t, func() {
+		for _, c := range cases {
+			c := c
+			Convey("When run worker by spec "+c.spec, func() {
+				res := make(chan time.Time)
+				clock := workers.NewManualClock(c.start)
+				wrk := workers.New(createWriterJob(res)).
+					ByCronSpec(c.spec, workers.CronParseMode(c.mode), workers.CronLocation(time.UTC)).
+					WithClock(clock)
+				So(wrk.Err(), ShouldBeNil)
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				go wrk.Run(ctx)
+
+				Convey("job should be executed at expected times", func() {
+					for _, expected := range c.expected {
+						clock.BlockUntil(1)
+						clock.Set(expected)
+						r, ok := readTimeWithTimeout(res, time.Second)
+						So(ok, ShouldBeTrue)
+						So(r.Equal(expected), ShouldBeTrue)
+					}
+				})
+			})
+		}
+	})
+
+	Convey("Given invalid cron specs", t, func() {
+		specs := map[string]workers.CronMode{
+			"0 0 * * * *":         workers.CronStandard,
+			"0 0 0 * * * 2025":    workers.CronDefault,
+			"0 0 0 32W * *":       workers.CronDefault,
+			"0 0 0 * * 5#6":       workers.CronDefault,
+			"0 0 0 * * * 1969":    workers.CronExtended,
+			"0 0 0 10-1 * *":      workers.CronDefault,
+			"0 0 0 * FOO *":       workers.CronDefault,
+			"@every 1 second":     workers.CronDefault,
+			"0 0 0 * * *":         workers.CronMode(100),
+			"CRON_TZ=Europe/Kyiv": workers.CronDefault,
+		}
+		for spec, mode := range specs {
+			_, err := workers.ParseCronSchedule(spec, workers.CronParseMode(mode))
+			So(err, ShouldNotBeNil)
+		}
+	})
+}
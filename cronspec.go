@@ -0,0 +1,470 @@
+package workers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronMode is cron spec fields layout selection
+type CronMode int
+
+const (
+	// CronDefault layout is seconds, minutes, hours, day of month, month
+	// and optional day of week, compatible with robfig/cron default parser
+	CronDefault CronMode = iota
+	// CronStandard layout is minutes, hours, day of month, month and day of week
+	CronStandard
+	// CronSecondsOptional layout is standard with optional leading seconds field
+	CronSecondsOptional
+	// CronExtended layout is seconds, minutes, hours, day of month, month,
+	// day of week and optional year
+	CronExtended
+)
+
+type (
+	// cronSpec is parsed cron spec, next returns next time after wall clock t
+	cronSpec interface {
+		next(t time.Time) time.Time
+	}
+
+	// everySpec is constant delay spec
+	everySpec time.Duration
+
+	// bitset of cron field values
+	bitset [4]uint64
+
+	// cronField describes values of cron spec field,
+	// values are stored to bitset with base offset
+	cronField struct {
+		name     string
+		min, max int
+		base     int
+		names    map[string]int
+	}
+
+	// cronExpr is cron spec expression, wall clock is matched with fields
+	cronExpr struct {
+		second, minute, hour, dom, month, dow, year bitset
+
+		domAny, dowAny, yearAny bool
+
+		lastDays    []int    // L and L-n, days before last day of month
+		lastWeekday bool     // LW, last weekday of month
+		nearest     []int    // nW, nearest weekday to day of month
+		lastDow     []int    // nL, last day of week in month
+		nthDow      [][2]int // n#k, k-th day of week in month
+	}
+)
+
+const (
+	minCronYear = 1970
+	maxCronYear = 2199
+	// cronYearsLimit is period of searching next time for spec without year
+	cronYearsLimit = 5
+)
+
+var (
+	secondField = cronField{"second", 0, 59, 0, nil}
+	minuteField = cronField{"minute", 0, 59, 0, nil}
+	hourField   = cronField{"hour", 0, 23, 0, nil}
+	domField    = cronField{"day of month", 1, 31, 0, nil}
+	monthField  = cronField{"month", 1, 12, 0, map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowField = cronField{"day of week", 0, 7, 0, map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+	yearField = cronField{"year", minCronYear, maxCronYear, minCronYear, nil}
+)
+
+// CronParseMode set cron spec fields layout, CronDefault by default
+func CronParseMode(mode CronMode) CronOption {
+	return func(s *cronSchedule) {
+		s.mode = mode
+	}
+}
+
+// parseCronSpec parse cron spec by fields layout mode.
+// Spec fields support lists, ranges, steps and names of months and days of week,
+// day of month supports L (last day), L-n (n days before last day), LW (last weekday),
+// nW (nearest weekday to day n) and ?, day of week supports nL (last day n of month),
+// n#k (k-th day n of month) and ?. Descriptors @yearly, @annually, @monthly,
+// @weekly, @daily, @midnight, @hourly and @every <duration> are supported too.
+func parseCronSpec(spec string, mode CronMode) (cronSpec, error) {
+	if spec == "" {
+		return nil, fmt.Errorf("empty spec string")
+	}
+	if spec[0] == '@' {
+		return parseCronDescriptor(spec)
+	}
+
+	fields := strings.Fields(spec)
+	count := len(fields)
+	switch mode {
+	case CronDefault:
+		if count < 5 || count > 6 {
+			return nil, fmt.Errorf("expected 5 to 6 fields, found %d: %s", count, spec)
+		}
+		if count == 5 {
+			fields = append(fields, "*")
+		}
+	case CronStandard:
+		if count != 5 {
+			return nil, fmt.Errorf("expected exactly 5 fields, found %d: %s", count, spec)
+		}
+		fields = append([]string{"0"}, fields...)
+	case CronSecondsOptional:
+		if count < 5 || count > 6 {
+			return nil, fmt.Errorf("expected 5 to 6 fields, found %d: %s", count, spec)
+		}
+		if count == 5 {
+			fields = append([]string{"0"}, fields...)
+		}
+	case CronExtended:
+		if count < 6 || count > 7 {
+			return nil, fmt.Errorf("expected 6 to 7 fields, found %d: %s", count, spec)
+		}
+	default:
+		return nil, fmt.Errorf("unknown cron parse mode %d", mode)
+	}
+	if len(fields) == 6 {
+		fields = append(fields, "*")
+	}
+
+	e := new(cronExpr)
+	var err error
+	if e.second, _, err = secondField.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if e.minute, _, err = minuteField.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if e.hour, _, err = hourField.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if err = e.parseDom(fields[3]); err != nil {
+		return nil, err
+	}
+	if e.month, _, err = monthField.parse(fields[4]); err != nil {
+		return nil, err
+	}
+	if err = e.parseDow(fields[5]); err != nil {
+		return nil, err
+	}
+	if e.year, e.yearAny, err = yearField.parse(fields[6]); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// parseCronDescriptor returns predefined spec for descriptor
+func parseCronDescriptor(descriptor string) (cronSpec, error) {
+	const every = "@every "
+	if strings.HasPrefix(descriptor, every) {
+		d, err := time.ParseDuration(strings.TrimSpace(descriptor[len(every):]))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse duration %s: %s", descriptor, err)
+		}
+		// delays of less than a second are not supported, fields less than a second are truncated
+		if d < time.Second {
+			d = time.Second
+		}
+		return everySpec(d - d%time.Second), nil
+	}
+
+	specs := map[string]string{
+		"@yearly":   "0 0 0 1 1 *",
+		"@annually": "0 0 0 1 1 *",
+		"@monthly":  "0 0 0 1 * *",
+		"@weekly":   "0 0 0 * * 0",
+		"@daily":    "0 0 0 * * *",
+		"@midnight": "0 0 0 * * *",
+		"@hourly":   "0 0 * * * *",
+	}
+	if spec, ok := specs[descriptor]; ok {
+		return parseCronSpec(spec, CronDefault)
+	}
+	return nil, fmt.Errorf("unrecognized descriptor: %s", descriptor)
+}
+
+func (d everySpec) next(t time.Time) time.Time {
+	return t.Add(time.Duration(d) - time.Duration(t.Nanosecond()))
+}
+
+// parseDom parse day of month field with L, W and ? modifiers
+func (e *cronExpr) parseDom(field string) error {
+	var items []string
+	for _, item := range strings.Split(field, ",") {
+		upper := strings.ToUpper(item)
+		switch {
+		case upper == "L":
+			e.lastDays = append(e.lastDays, 0)
+		case upper == "LW":
+			e.lastWeekday = true
+		case strings.HasPrefix(upper, "L-"):
+			n, err := strconv.Atoi(upper[2:])
+			if err != nil || n < 0 || n > 30 {
+				return fmt.Errorf("invalid day of month offset: %s", item)
+			}
+			e.lastDays = append(e.lastDays, n)
+		case strings.HasSuffix(upper, "W"):
+			n, err := strconv.Atoi(upper[:len(upper)-1])
+			if err != nil || n < domField.min || n > domField.max {
+				return fmt.Errorf("invalid nearest weekday: %s", item)
+			}
+			e.nearest = append(e.nearest, n)
+		default:
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	var err error
+	e.dom, e.domAny, err = domField.parse(strings.Join(items, ","))
+	return err
+}
+
+// parseDow parse day of week field with L, # and ? modifiers
+func (e *cronExpr) parseDow(field string) error {
+	var items []string
+	for _, item := range strings.Split(field, ",") {
+		upper := strings.ToUpper(item)
+		switch {
+		case len(upper) > 1 && strings.HasSuffix(upper, "L"):
+			wd, err := dowField.value(item[:len(item)-1])
+			if err != nil {
+				return err
+			}
+			e.lastDow = append(e.lastDow, wd%7)
+		case strings.Contains(upper, "#"):
+			parts := strings.SplitN(item, "#", 2)
+			wd, err := dowField.value(parts[0])
+			if err != nil {
+				return err
+			}
+			k, err := strconv.Atoi(parts[1])
+			if err != nil || k < 1 || k > 5 {
+				return fmt.Errorf("invalid day of week number: %s", item)
+			}
+			e.nthDow = append(e.nthDow, [2]int{wd % 7, k})
+		default:
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	var err error
+	e.dow, e.dowAny, err = dowField.parse(strings.Join(items, ","))
+	if e.dow.has(7) {
+		e.dow.set(0)
+	}
+	return err
+}
+
+// next returns next matched wall clock time after t, zero time if not found
+func (e *cronExpr) next(t time.Time) time.Time {
+	t = t.Add(time.Second - time.Duration(t.Nanosecond()))
+	h, m, s := t.Clock()
+	date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	limit := maxCronYear
+	if e.yearAny {
+		limit = t.Year() + cronYearsLimit
+	}
+	for date.Year() <= limit {
+		switch {
+		case !e.year.has(date.Year() - minCronYear):
+			date = time.Date(date.Year()+1, time.January, 1, 0, 0, 0, 0, time.UTC)
+		case !e.month.has(int(date.Month())):
+			date = time.Date(date.Year(), date.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		default:
+			if e.dayMatches(date) {
+				if hh, mm, ss, ok := e.clock(h, m, s); ok {
+					return date.Add(time.Duration(hh)*time.Hour +
+						time.Duration(mm)*time.Minute + time.Duration(ss)*time.Second)
+				}
+			}
+			date = date.AddDate(0, 0, 1)
+		}
+		h, m, s = 0, 0, 0
+	}
+	return time.Time{}
+}
+
+// clock returns first matched time of day not before h:m:s
+func (e *cronExpr) clock(h, m, s int) (int, int, int, bool) {
+	for hh := h; hh <= hourField.max; hh++ {
+		if !e.hour.has(hh) {
+			continue
+		}
+		for mm := 0; mm <= minuteField.max; mm++ {
+			if (hh == h && mm < m) || !e.minute.has(mm) {
+				continue
+			}
+			for ss := 0; ss <= secondField.max; ss++ {
+				if (hh == h && mm == m && ss < s) || !e.second.has(ss) {
+					continue
+				}
+				return hh, mm, ss, true
+			}
+		}
+	}
+	return 0, 0, 0, false
+}
+
+// dayMatches returns true if day of month and day of week are matched,
+// if both fields are restricted then any of them should be matched
+func (e *cronExpr) dayMatches(date time.Time) bool {
+	day, wd := date.Day(), int(date.Weekday())
+	last := daysIn(date)
+
+	domMatch := e.dom.has(day)
+	for _, n := range e.lastDays {
+		domMatch = domMatch || day == last-n
+	}
+	if e.lastWeekday {
+		domMatch = domMatch || day == nearestWeekday(date, last)
+	}
+	for _, n := range e.nearest {
+		domMatch = domMatch || day == nearestWeekday(date, n)
+	}
+
+	dowMatch := e.dow.has(wd)
+	for _, n := range e.lastDow {
+		dowMatch = dowMatch || (wd == n && day+7 > last)
+	}
+	for _, nk := range e.nthDow {
+		dowMatch = dowMatch || (wd == nk[0] && (day-1)/7+1 == nk[1])
+	}
+
+	if e.domAny || e.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// daysIn returns count of days in month of date
+func daysIn(date time.Time) int {
+	return time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// nearestWeekday returns weekday nearest to day of month of date without leaving month
+func nearestWeekday(date time.Time, day int) int {
+	last := daysIn(date)
+	if day > last {
+		day = last
+	}
+	switch time.Date(date.Year(), date.Month(), day, 0, 0, 0, 0, time.UTC).Weekday() {
+	case time.Saturday:
+		if day == 1 {
+			return day + 2
+		}
+		return day - 1
+	case time.Sunday:
+		if day == last {
+			return day - 2
+		}
+		return day + 1
+	}
+	return day
+}
+
+// parse field with lists, ranges, steps and names,
+// returns true if field matches any value
+func (f cronField) parse(field string) (bitset, bool, error) {
+	var bits bitset
+	if field == "*" || field == "?" {
+		bits.fill(f.min-f.base, f.max-f.base, 1)
+		return bits, true, nil
+	}
+
+	for _, expr := range strings.Split(field, ",") {
+		if err := f.parseRange(&bits, expr); err != nil {
+			return bits, false, err
+		}
+	}
+	return bits, false, nil
+}
+
+// parseRange parse range expression: *, n, n-m, */s, n/s, n-m/s
+func (f cronField) parseRange(bits *bitset, expr string) error {
+	rangeAndStep := strings.Split(expr, "/")
+	lowAndHigh := strings.Split(rangeAndStep[0], "-")
+	if len(rangeAndStep) > 2 || len(lowAndHigh) > 2 {
+		return fmt.Errorf("invalid %s expression: %s", f.name, expr)
+	}
+
+	var (
+		low, high int
+		err       error
+	)
+	if lowAndHigh[0] == "*" || lowAndHigh[0] == "?" {
+		if len(lowAndHigh) > 1 {
+			return fmt.Errorf("invalid %s expression: %s", f.name, expr)
+		}
+		low, high = f.min, f.max
+	} else {
+		if low, err = f.value(lowAndHigh[0]); err != nil {
+			return err
+		}
+		high = low
+		if len(lowAndHigh) > 1 {
+			if high, err = f.value(lowAndHigh[1]); err != nil {
+				return err
+			}
+		}
+	}
+
+	step := 1
+	if len(rangeAndStep) > 1 {
+		if step, err = strconv.Atoi(rangeAndStep[1]); err != nil || step <= 0 {
+			return fmt.Errorf("invalid %s step: %s", f.name, expr)
+		}
+		// n/s means from n to max with step s
+		if len(lowAndHigh) == 1 {
+			high = f.max
+		}
+	}
+
+	if low > high {
+		return fmt.Errorf("beginning of %s range (%d) beyond end of range (%d): %s", f.name, low, high, expr)
+	}
+	bits.fill(low-f.base, high-f.base, step)
+	return nil
+}
+
+// value returns number or named value of field
+func (f cronField) value(expr string) (int, error) {
+	if v, ok := f.names[strings.ToLower(expr)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(expr)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s value %q: %s", f.name, expr, err)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("%s value (%d) out of range [%d, %d]", f.name, v, f.min, f.max)
+	}
+	return v, nil
+}
+
+func (b *bitset) fill(from, to, step int) {
+	for i := from; i <= to; i += step {
+		b.set(i)
+	}
+}
+
+func (b *bitset) set(i int) {
+	b[i/64] |= 1 << uint(i%64)
+}
+
+func (b bitset) has(i int) bool {
+	return i >= 0 && i < 256 && b[i/64]&(1<<uint(i%64)) != 0
+}
@@ -0,0 +1,179 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrDependencyCycle workers of group depend on each other
+	ErrDependencyCycle = errors.New("dependency cycle")
+	// ErrUnknownDependency worker depends on worker which is not added to group
+	ErrUnknownDependency = errors.New("unknown dependency")
+	// ErrDependencyFailed worker is not started because dependency is finished before it's ready
+	ErrDependencyFailed = errors.New("dependency failed")
+)
+
+// DependsOn set workers which should be ready before worker is started in group,
+// e.g. cache warmer before HTTP refreshers. Dependencies should be added to same group,
+// worker added to runned group can depend only on workers which are not finished yet
+func (w *Worker) DependsOn(others ...*Worker) *Worker {
+	w.deps = append(w.deps, others...)
+	return w
+}
+
+// Ready signal readiness of worker from job context, workers which depend on it are started.
+// Worker is also ready when its first run completed without error
+func Ready(ctx context.Context) {
+	if h, ok := ctx.Value(handleKey{}).(*Handle); ok {
+		h.setReady()
+	}
+}
+
+// checkDependencies returns error if dependency of worker is not in handles
+// or workers depend on each other, dependencies of finished workers,
+// e.g. stopped before start, are not checked
+func checkDependencies(handles []*Handle) error {
+	byWorker := make(map[*Worker]*Handle, len(handles))
+	for _, h := range handles {
+		byWorker[h.w] = h
+	}
+	for _, h := range handles {
+		if h.finished() {
+			continue
+		}
+		for _, dep := range h.w.deps {
+			if byWorker[dep] == nil {
+				return fmt.Errorf("%w: worker %s depends on worker %s which is not added to group",
+					ErrUnknownDependency, workerName(h.w), workerName(dep))
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[*Worker]int, len(handles))
+	var path []*Worker
+	var visit func(w *Worker) error
+	visit = func(w *Worker) error {
+		switch state[w] {
+		case visiting:
+			// cycle is reported from dependent to dependency, e.g. "a" -> "b" -> "a"
+			var names []string
+			for i := len(path) - 1; i >= 0; i-- {
+				if path[i] == w {
+					for _, p := range path[i:] {
+						names = append(names, workerName(p))
+					}
+					break
+				}
+			}
+			names = append(names, workerName(w))
+			return fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(names, " -> "))
+		case visited:
+			return nil
+		}
+		if byWorker[w].finished() {
+			// finished worker is never started
+			return nil
+		}
+
+		state[w] = visiting
+		path = append(path, w)
+		for _, dep := range w.deps {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[w] = visited
+		return nil
+	}
+	for _, h := range handles {
+		if h.finished() {
+			continue
+		}
+		if err := visit(h.w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bindDependencies set dependencies of handles to handles of dependency workers from all,
+// finished handles are not bound
+func bindDependencies(handles, all []*Handle) {
+	byWorker := make(map[*Worker]*Handle, len(all))
+	for _, h := range all {
+		byWorker[h.w] = h
+	}
+	for _, h := range handles {
+		if h.finished() {
+			continue
+		}
+		h.deps = make([]*Handle, 0, len(h.w.deps))
+		for _, dep := range h.w.deps {
+			h.deps = append(h.deps, byWorker[dep])
+		}
+	}
+}
+
+// waitDependencies wait until dependencies of worker are ready or worker is stopped before.
+// Returns context error if context is done before or error if dependency is finished
+// without readiness, e.g. failed or stopped
+func (h *Handle) waitDependencies(ctx context.Context) error {
+	for _, dep := range h.deps {
+		select {
+		case <-dep.ready:
+		case <-dep.done:
+			select {
+			case <-dep.ready:
+			default:
+				return dependencyError(dep)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-h.done:
+			return nil
+		}
+	}
+	return nil
+}
+
+// dependencyError returns error of dependency finished before it's ready
+func dependencyError(dep *Handle) error {
+	if err := dep.Err(); err != nil {
+		return fmt.Errorf("%w: worker %s is %s before ready: %v", ErrDependencyFailed, workerName(dep.w), dep.Status(), err)
+	}
+	return fmt.Errorf("%w: worker %s is %s before ready", ErrDependencyFailed, workerName(dep.w), dep.Status())
+}
+
+// finished returns true if worker is finished or stopped before start
+func (h *Handle) finished() bool {
+	select {
+	case <-h.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// setReady mark worker as ready
+func (h *Handle) setReady() {
+	h.readyOnce.Do(func() {
+		close(h.ready)
+	})
+}
+
+// workerName returns quoted worker name for errors
+func workerName(w *Worker) string {
+	if w.name == "" {
+		return fmt.Sprintf("<unnamed %p>", w)
+	}
+	return fmt.Sprintf("%q", w.name)
+}
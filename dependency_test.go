@@ -0,0 +1,176 @@
+package workers_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jenchik/workers"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDependencies(t *testing.T) {
+	Convey("Given group with cache warmer and refresher which depends on it", t, func() {
+		var warmed int32
+		started := make(chan bool, 1)
+		refresher := func(ctx context.Context) {
+			started <- atomic.LoadInt32(&warmed) == 1
+			<-ctx.Done()
+		}
+
+		wg := workers.NewGroup(context.Background())
+		Reset(func() {
+			wg.Stop()
+			wg.Wait(nil)
+		})
+
+		Convey("When warmer signals readiness by context", func() {
+			warmer := workers.New(func(ctx context.Context) {
+				time.Sleep(20 * time.Millisecond)
+				atomic.StoreInt32(&warmed, 1)
+				workers.Ready(ctx)
+				<-ctx.Done()
+			})
+			handles, err := wg.Add(workers.New(refresher).DependsOn(warmer), warmer)
+			So(err, ShouldBeNil)
+			So(wg.Run(), ShouldBeNil)
+
+			Convey("refresher should be started after warmer is ready", func() {
+				So(<-started, ShouldBeTrue)
+				So(handles[0].Status(), ShouldEqual, workers.StatusRunning)
+			})
+		})
+
+		Convey("When first run of warmer is completed", func() {
+			warmer := workers.New(func(ctx context.Context) {
+				time.Sleep(20 * time.Millisecond)
+				atomic.StoreInt32(&warmed, 1)
+			})
+			_, err := wg.Add(warmer, workers.New(refresher).DependsOn(warmer))
+			So(err, ShouldBeNil)
+			So(wg.Run(), ShouldBeNil)
+
+			Convey("refresher should be started after warmer run", func() {
+				So(<-started, ShouldBeTrue)
+			})
+		})
+
+		Convey("When dependent worker is added to runned group", func() {
+			So(wg.Run(), ShouldBeNil)
+
+			Convey("unknown dependency should be error", func() {
+				_, err := wg.Add(workers.New(refresher).DependsOn(workers.New(refresher)))
+				So(errors.Is(err, workers.ErrUnknownDependency), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given group with worker which depends on failing worker", t, func() {
+		errLoad := errors.New("load failed")
+		var started int32
+		dep := workers.NewE(func(ctx context.Context) error {
+			return errLoad
+		}).Named("loader")
+
+		wg := workers.NewGroup(context.Background())
+		Reset(func() {
+			wg.Stop()
+			wg.Wait(nil)
+		})
+		handles, err := wg.Add(workers.New(func(ctx context.Context) {
+			atomic.AddInt32(&started, 1)
+		}).DependsOn(dep), dep)
+		So(err, ShouldBeNil)
+		So(wg.Run(), ShouldBeNil)
+
+		Convey("dependent worker should be failed without start", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			err := handles[0].Wait(ctx)
+			So(errors.Is(err, workers.ErrDependencyFailed), ShouldBeTrue)
+			So(err.Error(), ShouldEqual, `dependency failed: worker "loader" is failed before ready: load failed`)
+			So(handles[0].Status(), ShouldEqual, workers.StatusFailed)
+			So(atomic.LoadInt32(&started), ShouldEqual, 0)
+		})
+	})
+
+	Convey("Given group with worker which depends on stopped worker", t, func() {
+		dep := workers.New(func(ctx context.Context) {
+			<-ctx.Done()
+		}).Named("loader")
+
+		wg := workers.NewGroup(context.Background())
+		Reset(func() {
+			wg.Stop()
+			wg.Wait(nil)
+		})
+		handles, err := wg.Add(workers.New(func(context.Context) {}).DependsOn(dep), dep)
+		So(err, ShouldBeNil)
+		handles[1].Stop()
+		So(wg.Run(), ShouldBeNil)
+
+		Convey("dependent worker should be failed without start", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			err := handles[0].Wait(ctx)
+			So(errors.Is(err, workers.ErrDependencyFailed), ShouldBeTrue)
+			So(err.Error(), ShouldEqual, `dependency failed: worker "loader" is stopped before ready`)
+		})
+	})
+
+	Convey("Given group with workers which depend on each other", t, func() {
+		var starts int32
+		job := func(ctx context.Context) {
+			atomic.AddInt32(&starts, 1)
+		}
+		a := workers.New(job).Named("a")
+		b := workers.New(job).Named("b").DependsOn(a)
+		a.DependsOn(b)
+
+		wg := workers.NewGroup(context.Background())
+		Reset(func() {
+			wg.Stop()
+			wg.Wait(nil)
+		})
+		_, err := wg.Add(a, b, workers.New(job).Named("c").DependsOn(b))
+		So(err, ShouldBeNil)
+
+		Convey("Run should return cycle error without starting workers", func() {
+			err := wg.Run()
+			So(errors.Is(err, workers.ErrDependencyCycle), ShouldBeTrue)
+			So(err.Error(), ShouldEqual, `dependency cycle: "a" -> "b" -> "a"`)
+
+			time.Sleep(10 * time.Millisecond)
+			So(atomic.LoadInt32(&starts), ShouldEqual, 0)
+		})
+	})
+
+	Convey("Given group with worker which depends on not added worker", t, func() {
+		wg := workers.NewGroup(context.Background())
+		Reset(wg.Stop)
+		started := make(chan struct{})
+		handles, err := wg.Add(
+			workers.New(func(context.Context) {}).Named("a").DependsOn(workers.New(nil).Named("b")),
+			workers.New(func(context.Context) { close(started) }).Named("c"),
+		)
+		So(err, ShouldBeNil)
+
+		Convey("Run should return unknown dependency error", func() {
+			err := wg.Run()
+			So(errors.Is(err, workers.ErrUnknownDependency), ShouldBeTrue)
+			So(err.Error(), ShouldContainSubstring, `worker "a" depends on worker "b"`)
+
+			Convey("group should be runned after worker is stopped", func() {
+				handles[0].Stop()
+				So(wg.Run(), ShouldBeNil)
+				select {
+				case <-started:
+				case <-time.After(time.Second):
+					So("worker is not started", ShouldBeEmpty)
+				}
+			})
+		})
+	})
+}
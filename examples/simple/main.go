@@ -23,9 +23,9 @@ func main() {
 		// example close resources or recover
 		log.Println("Worker #2: closed")
 	})
-	w3 := workers.New(func(context.Context) { panic("test") }).ByTimer(time.Second * 2).WithDone(func() {
-		e := recover()
-		log.Println("Worker #3: recover:", e)
+	w3 := workers.New(func(context.Context) { panic("test") }).ByTimer(time.Second * 2).WithRecover(func(_ context.Context, p *workers.PanicError) {
+		// each run is recovered, worker continues work by schedule
+		log.Println("Worker #3: recover:", p.Value)
 	})
 
 	g := workers.NewGroup(context.Background())
@@ -0,0 +1,69 @@
+package workers
+
+import (
+	"context"
+	"errors"
+)
+
+// FailFastMode is mode of group stop when worker failed fatally
+type FailFastMode int
+
+const (
+	// FailFastFirst cancel group context when worker failed, Group.Wait returns first error
+	FailFastFirst FailFastMode = iota + 1
+	// FailFastJoin cancel group context when worker failed,
+	// Group.Wait returns joined errors of all failed workers
+	FailFastJoin
+)
+
+// FatalError is error of job run which stops worker, see Fatal
+type FatalError struct {
+	Err error
+}
+
+// WithFailFast set group stop when any worker failed fatally like errgroup:
+// Run of worker returns error, e.g. job of worker without schedule failed,
+// run of scheduled worker returned error wrapped by Fatal or panic of worker was recovered.
+// Should be called before adding workers
+func (g *Group) WithFailFast(m FailFastMode) *Group {
+	g.failFast = m
+	return g
+}
+
+// Fatal returns error of job run which stops scheduled worker,
+// worker Run returns it. Returns nil if err is nil
+func Fatal(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &FatalError{Err: err}
+}
+
+func (e *FatalError) Error() string {
+	return "fatal: " + e.Err.Error()
+}
+
+func (e *FatalError) Unwrap() error {
+	return e.Err
+}
+
+// isFatal returns true if err is fatal error of job run
+func isFatal(err error) bool {
+	var fatal *FatalError
+	return errors.As(err, &fatal)
+}
+
+// failWorker stop group with error of worker. Error of worker finished after group is stopped
+// is ignored, except of FailFastJoin mode where errors of workers failed while failed group
+// is stopping are joined
+func (g *Group) failWorker(ctx context.Context, err error) {
+	if ctx.Err() != nil {
+		g.mu.Lock()
+		join := g.failFast == FailFastJoin && len(g.errs) > 0 && !errors.Is(err, ctx.Err())
+		g.mu.Unlock()
+		if !join {
+			return
+		}
+	}
+	g.fail(err)
+}
@@ -0,0 +1,142 @@
+package workers_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jenchik/workers"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFailFast(t *testing.T) {
+	errA, errB := errors.New("a failed"), errors.New("b failed")
+	blocking := func(ctx context.Context) {
+		<-ctx.Done()
+	}
+	failing := func(err error) workers.JobE {
+		return func(context.Context) error {
+			return err
+		}
+	}
+	canceled := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	wait := func(wg *workers.Group) error {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		return wg.Wait(ctx)
+	}
+
+	Convey("Given group which fails fast by first error", t, func() {
+		wg := workers.NewGroup(context.Background()).WithFailFast(workers.FailFastFirst)
+
+		Convey("When worker failed", func() {
+			_, err := wg.Add(workers.New(blocking), workers.NewE(failing(errA)))
+			So(err, ShouldBeNil)
+			wg.Run()
+
+			Convey("group should be stopped with worker error", func() {
+				So(wait(wg), ShouldEqual, errA)
+			})
+		})
+
+		Convey("When scheduled worker run failed fatally", func() {
+			clock := workers.NewManualClock(time.Now())
+			_, err := wg.Add(
+				workers.New(blocking),
+				workers.NewE(failing(workers.Fatal(errA))).ByTicker(time.Second).WithClock(clock),
+			)
+			So(err, ShouldBeNil)
+			wg.Run()
+			clock.BlockUntil(1)
+			clock.Add(time.Second)
+
+			Convey("group should be stopped with fatal error", func() {
+				err := wait(wg)
+				var fatal *workers.FatalError
+				So(errors.As(err, &fatal), ShouldBeTrue)
+				So(errors.Is(err, errA), ShouldBeTrue)
+			})
+		})
+
+		Convey("When worker panicked", func() {
+			_, err := wg.Add(workers.New(blocking), workers.New(func(context.Context) { panic("test") }))
+			So(err, ShouldBeNil)
+			wg.Run()
+
+			Convey("group should be stopped with panic error", func() {
+				var p *workers.PanicError
+				So(errors.As(wait(wg), &p), ShouldBeTrue)
+				So(p.Value, ShouldEqual, "test")
+			})
+		})
+
+		Convey("When group is stopped", func() {
+			_, err := wg.Add(workers.New(blocking))
+			So(err, ShouldBeNil)
+			wg.Run()
+			wg.Stop()
+
+			Convey("Wait should return nil", func() {
+				So(wait(wg), ShouldBeNil)
+			})
+		})
+
+		Convey("When group with worker returning context error is stopped", func() {
+			_, err := wg.Add(workers.NewE(canceled))
+			So(err, ShouldBeNil)
+			wg.Run()
+			wg.Stop()
+
+			Convey("Wait should return nil", func() {
+				So(wait(wg), ShouldBeNil)
+			})
+		})
+
+		Convey("When worker returning context error is stopped by handle", func() {
+			var started sync.WaitGroup
+			started.Add(2)
+			handles, err := wg.Add(
+				workers.NewE(func(ctx context.Context) error {
+					started.Done()
+					return canceled(ctx)
+				}),
+				workers.New(func(ctx context.Context) {
+					started.Done()
+					<-ctx.Done()
+				}),
+			)
+			So(err, ShouldBeNil)
+			wg.Run()
+			started.Wait()
+			handles[0].Stop()
+
+			Convey("other workers should keep running", func() {
+				So(handles[0].Wait(nil), ShouldEqual, context.Canceled)
+				So(handles[0].Status(), ShouldEqual, workers.StatusStopped)
+				time.Sleep(10 * time.Millisecond)
+				So(handles[1].Status(), ShouldEqual, workers.StatusRunning)
+
+				wg.Stop()
+				So(wait(wg), ShouldBeNil)
+			})
+		})
+	})
+
+	Convey("Given group which fails fast by joined errors", t, func() {
+		wg := workers.NewGroup(context.Background()).WithFailFast(workers.FailFastJoin)
+		_, err := wg.Add(workers.NewE(failing(errA)), workers.NewE(failing(errB)))
+		So(err, ShouldBeNil)
+		wg.Run()
+
+		Convey("Wait should return all errors", func() {
+			err := wait(wg)
+			So(errors.Is(err, errA), ShouldBeTrue)
+			So(errors.Is(err, errB), ShouldBeTrue)
+		})
+	})
+}
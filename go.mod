@@ -1,6 +1,11 @@
 module github.com/jenchik/workers
 
+go 1.20
+
+require github.com/smartystreets/goconvey v0.0.0-20190222223459-a17d461953aa
+
 require (
-	github.com/robfig/cron v0.0.0-20180505203441-b41be1df6967
-	github.com/smartystreets/goconvey v0.0.0-20190222223459-a17d461953aa
+	github.com/gopherjs/gopherjs v0.0.0-20181017120253-0766667cb4d1 // indirect
+	github.com/jtolds/gls v4.2.1+incompatible // indirect
+	github.com/smartystreets/assertions v0.0.0-20180927180507-b2de0cb4f26d // indirect
 )
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"sync"
+	"time"
 )
 
 // ErrGroupStopped group error message already stopped
@@ -12,16 +13,27 @@ var ErrGroupStopped = errors.New("group is already stopped")
 // Group of workers controlling background jobs execution
 // allows graceful stop all running background jobs
 type Group struct {
-	add     chan Job
-	done    chan struct{}
-	running chan struct{}
-	stop    context.CancelFunc
+	add      chan JobE
+	done     chan struct{}
+	running  chan struct{}
+	stop     context.CancelFunc
+	onError  ErrorHandler
+	clock    Clock
+	sup      *supervisor
+	failFast FailFastMode
+	timeouts map[int]time.Duration
+	mu       sync.Mutex
+	errs     []error
+	handles  []*Handle
+	canceled time.Time
+	stacks   bool
+	started  bool
 }
 
 // NewGroup yield new workers group
 func NewGroup(ctx context.Context) *Group {
 	g := &Group{
-		add:     make(chan Job),
+		add:     make(chan JobE),
 		done:    make(chan struct{}),
 		running: make(chan struct{}),
 	}
@@ -30,15 +42,33 @@ func NewGroup(ctx context.Context) *Group {
 	return g
 }
 
+// WithErrorHandler set handler for errors returned by job runs of all group workers.
+// Should be called before adding workers
+func (g *Group) WithErrorHandler(h ErrorHandler) *Group {
+	g.onError = h
+	return g
+}
+
 func (g *Group) run(ctx context.Context) {
 	defer close(g.done)
 	wg := new(sync.WaitGroup)
-	jobs := make([]Job, 0, 8)
-	do := func(j Job) {
+	jobs := make([]JobE, 0, 8)
+	do := func(j JobE) {
+		ctx := ctx
+		if g.onError != nil {
+			ctx = context.WithValue(ctx, errorHandlerKey{}, g.onError)
+		}
+		if g.clock != nil {
+			ctx = withClock(ctx, g.clock)
+		}
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			j(ctx)
+			// errors of runs are passed to group error handler by worker,
+			// error of worker is fatal if group fails fast
+			if err := j(ctx); err != nil && g.failFast != 0 {
+				g.failWorker(ctx, err)
+			}
 		}()
 	}
 	for {
@@ -55,25 +85,94 @@ func (g *Group) run(ctx context.Context) {
 			}
 			jobs = nil
 		case <-ctx.Done():
+			g.mu.Lock()
+			g.canceled = time.Now()
+			g.mu.Unlock()
 			wg.Wait()
 			return
 		}
 	}
 }
 
-// Add workers to group, if group runned then start worker immediately
-func (g *Group) Add(workers ...*Worker) error {
+// Add workers to group, if group runned then start worker immediately.
+// Worker is removed from group when it's finished.
+// Returns handle of each added worker, handle is nil for nil worker or worker without job.
+// Returns worker configuration error or dependencies error of runned group without adding any worker
+func (g *Group) Add(workers ...*Worker) ([]*Handle, error) {
 	for _, worker := range workers {
-		if worker == nil || worker.job == nil {
+		if worker == nil {
 			continue
 		}
+		if err := worker.Err(); err != nil {
+			return nil, err
+		}
+	}
+	handles := make([]*Handle, len(workers))
+	added := make([]*Handle, 0, len(workers))
+	for i, worker := range workers {
+		if worker != nil && worker.job != nil {
+			handles[i] = newHandle(worker, g)
+			added = append(added, handles[i])
+		}
+	}
+
+	g.mu.Lock()
+	all := append(g.handles[:len(g.handles):len(g.handles)], added...)
+	if g.started {
+		if err := checkDependencies(all); err != nil {
+			g.mu.Unlock()
+			return nil, err
+		}
+		bindDependencies(added, all)
+	}
+	g.handles = all
+	g.mu.Unlock()
+
+	for i, h := range handles {
+		if h == nil {
+			continue
+		}
+		if g.sup != nil {
+			g.sup.add(h)
+		}
+		h := h
+		run := func(ctx context.Context) error {
+			defer g.remove(h)
+			return h.run(ctx)
+		}
 		select {
-		case g.add <- worker.Run:
+		case g.add <- run:
 		case <-g.done:
-			return ErrGroupStopped
+			for _, h := range handles[i:] {
+				if h != nil {
+					h.Stop()
+					g.remove(h)
+				}
+			}
+			return handles, ErrGroupStopped
 		}
 	}
-	return nil
+	return handles, nil
+}
+
+// remove finished worker handle from group
+func (g *Group) remove(h *Handle) {
+	g.mu.Lock()
+	g.handles = removeHandle(g.handles, h)
+	g.mu.Unlock()
+	if g.sup != nil {
+		g.sup.remove(h)
+	}
+}
+
+// removeHandle returns handles without h, handles are not modified
+func removeHandle(handles []*Handle, h *Handle) []*Handle {
+	for i, c := range handles {
+		if c == h {
+			return append(handles[:i:i], handles[i+1:]...)
+		}
+	}
+	return handles
 }
 
 // OnDemand link worker with group and then run by on demand
@@ -81,12 +180,27 @@ func (g *Group) OnDemand(worker *Worker) *onDemand {
 	return &onDemand{g, worker}
 }
 
-// Run starting each worker in separate goroutine with wait.Group control
-func (g *Group) Run() {
+// Run starting each worker in separate goroutine with wait.Group control,
+// worker is started after workers it depends on are ready.
+// Returns error without starting workers if dependency of worker is not added
+// to group or workers depend on each other
+func (g *Group) Run() error {
+	g.mu.Lock()
+	if !g.started {
+		if err := checkDependencies(g.handles); err != nil {
+			g.mu.Unlock()
+			return err
+		}
+		bindDependencies(g.handles, g.handles)
+		g.started = true
+	}
+	g.mu.Unlock()
+
 	select {
 	case g.running <- struct{}{}:
 	case <-g.done:
 	}
+	return nil
 }
 
 // Stop cancel workers context
@@ -95,20 +209,46 @@ func (g *Group) Stop() {
 }
 
 // Wait until all runned workers was completed.
-// Be careful! It can be deadlock if some worker hanging
+// Returns error which stopped group, e.g. ErrTooManyRestarts, or context error.
+// If context is done before workers are finished, returns *HungWorkersError
+// with still running workers, which wraps context error.
+// Be careful! It can be deadlock if some worker hanging and context is nil
 func (g *Group) Wait(ctx context.Context) (err error) {
 	if ctx == nil {
 		<-g.done
-		return
+		return g.Err()
 	}
 	select {
 	case <-ctx.Done():
-		err = ctx.Err()
+		err = g.hung(ctx.Err())
 	case <-g.done:
+		err = g.Err()
 	}
 	return
 }
 
+// Err returns error which stopped group, all errors of failed workers are joined by FailFastJoin mode
+func (g *Group) Err() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	switch {
+	case len(g.errs) == 0:
+		return nil
+	case g.failFast == FailFastJoin:
+		return errors.Join(g.errs...)
+	}
+	return g.errs[0]
+}
+
+// fail stop group with error
+func (g *Group) fail(err error) {
+	g.mu.Lock()
+	g.errs = append(g.errs, err)
+	g.mu.Unlock()
+	g.stop()
+}
+
 // AddGroup add groups to group as child
 func (g *Group) AddGroup(groups ...*Group) error {
 	w := New(func(ctx context.Context) {
@@ -120,5 +260,6 @@ func (g *Group) AddGroup(groups ...*Group) error {
 			child.Wait(nil)
 		}
 	})
-	return g.Add(w)
+	_, err := g.Add(w)
+	return err
 }
@@ -2,6 +2,7 @@ package workers_test
 
 import (
 	"context"
+	"errors"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -89,3 +90,34 @@ func readFromChannelWithTimeout(result chan struct{}) bool {
 		return false
 	}
 }
+
+func TestGroupErrorHandler(t *testing.T) {
+	Convey("Given group with error handler", t, func() {
+		var (
+			errJob = errors.New("job failed")
+			errs   = make(chan error, 1)
+		)
+		wk := workers.NewGroup(context.Background()).
+			WithErrorHandler(func(ctx context.Context, err error) {
+				errs <- err
+			})
+
+		Convey("When run failing worker", func() {
+			wk.Add(workers.NewE(func(ctx context.Context) error {
+				return errJob
+			}))
+			wk.Run()
+
+			Convey("job error should be passed to group handler", func() {
+				select {
+				case err := <-errs:
+					So(err, ShouldEqual, errJob)
+				case <-time.After(time.Second):
+					So("timeout", ShouldBeEmpty)
+				}
+				wk.Stop()
+				So(wk.Wait(nil), ShouldBeNil)
+			})
+		})
+	})
+}
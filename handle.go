@@ -0,0 +1,367 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Status is state of worker added to group
+type Status int32
+
+const (
+	// StatusPending worker is added, but group is not runned yet
+	StatusPending Status = iota
+	// StatusRunning worker is running
+	StatusRunning
+	// StatusPaused worker is running, but scheduled runs are skipped
+	StatusPaused
+	// StatusStopped worker is stopped by handle or group
+	StatusStopped
+	// StatusCompleted worker is completed naturally
+	StatusCompleted
+	// StatusFailed worker is completed with error
+	StatusFailed
+)
+
+var statusNames = [...]string{"pending", "running", "paused", "stopped", "completed", "failed"}
+
+type (
+	// Handle controls worker added to group
+	Handle struct {
+		w       *Worker
+		sup     *supervisor
+		recover bool
+		paused  int32
+		mu      sync.Mutex
+		status  Status
+		stopped bool
+		err     error
+		cancel  context.CancelFunc
+		exited  chan struct{}
+		gate    chan struct{}
+		goid    uint64
+		runs    map[uint64]int
+		stopAt  time.Time
+		deps    []*Handle
+		ready   chan struct{}
+		trigger chan struct{}
+		done    chan struct{}
+
+		readyOnce sync.Once
+	}
+
+	handleKey struct{}
+)
+
+func (s Status) String() string {
+	if s < 0 || int(s) >= len(statusNames) {
+		return "unknown"
+	}
+	return statusNames[s]
+}
+
+// newHandle returns handle of group worker, worker is restarted by group supervisor,
+// panic of worker is recovered if group is supervised or fails fast
+func newHandle(w *Worker, g *Group) *Handle {
+	return &Handle{
+		w:       w,
+		sup:     g.sup,
+		recover: g.sup != nil || g.failFast != 0,
+		ready:   make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// Worker returns controlled worker
+func (h *Handle) Worker() *Worker {
+	return h.w
+}
+
+// Pause worker, scheduled runs are skipped and counted in worker stats
+// until worker is resumed, running run is not interrupted
+func (h *Handle) Pause() {
+	atomic.StoreInt32(&h.paused, 1)
+}
+
+// Resume paused worker, worker runs on its regular schedule
+func (h *Handle) Resume() {
+	atomic.StoreInt32(&h.paused, 0)
+}
+
+// IsPaused returns true if worker is paused
+func (h *Handle) IsPaused() bool {
+	return atomic.LoadInt32(&h.paused) == 1
+}
+
+// Stop worker by cancel its context, other group workers keep running.
+// Pending worker is never started
+func (h *Handle) Stop() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch h.status {
+	case StatusPending:
+		h.status = StatusStopped
+		close(h.done)
+	case StatusRunning:
+		if !h.stopped {
+			h.stopped, h.stopAt = true, time.Now()
+		}
+		if h.cancel != nil {
+			h.cancel()
+		}
+	}
+}
+
+// Done returns channel which is closed when worker is finished
+func (h *Handle) Done() <-chan struct{} {
+	return h.done
+}
+
+// Wait until worker is finished, returns error of worker run.
+// Returns context error if context is done before
+func (h *Handle) Wait(ctx context.Context) error {
+	if ctx == nil {
+		<-h.done
+		return h.Err()
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-h.done:
+		return h.Err()
+	}
+}
+
+// Err returns error of finished worker run
+func (h *Handle) Err() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.err
+}
+
+// Status returns current worker status
+func (h *Handle) Status() Status {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.status == StatusRunning && h.IsPaused() {
+		return StatusPaused
+	}
+	return h.status
+}
+
+// TriggerNow run job of scheduled worker now out of schedule by worker concurrency policy,
+// run is executed even if worker is paused.
+// Returns false if worker isn't running by schedule or previous trigger is not executed yet
+func (h *Handle) TriggerNow() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.trigger == nil {
+		return false
+	}
+	select {
+	case h.trigger <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// run worker with handle in context after dependencies are ready,
+// worker is failed if dependency is finished before ready.
+// Worker is started again while supervisor restarts it.
+// Returns nil if worker is stopped by handle or returned context error of stopped group
+func (h *Handle) run(ctx context.Context) error {
+	ctx = context.WithValue(ctx, handleKey{}, h)
+	err := h.waitDependencies(ctx)
+
+	h.mu.Lock()
+	switch {
+	case h.status != StatusPending:
+		// stopped before start
+		h.mu.Unlock()
+		return nil
+	case err != nil && err == ctx.Err():
+		h.status = StatusStopped
+		close(h.done)
+		h.mu.Unlock()
+		return nil
+	case err != nil:
+		h.status, h.err = StatusFailed, err
+		close(h.done)
+		h.mu.Unlock()
+		return err
+	}
+	h.status = StatusRunning
+	h.mu.Unlock()
+
+	err = h.runOnce(ctx)
+	for h.sup != nil && h.sup.restart(ctx, h, err) {
+		err = h.runOnce(ctx)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	switch {
+	case ctx.Err() != nil || h.stopped:
+		h.status = StatusStopped
+	case err != nil:
+		h.status = StatusFailed
+	default:
+		h.status = StatusCompleted
+	}
+	h.err = err
+	close(h.done)
+	if h.stopped || ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+		// worker is stopped by handle or returned context error of stopped group
+		return nil
+	}
+	return err
+}
+
+// runOnce run worker in own context, which can be canceled by Stop or supervisor.
+// Recovered panic of worker is returned as *PanicError
+func (h *Handle) runOnce(ctx context.Context) (err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	exited := make(chan struct{})
+
+	h.mu.Lock()
+	if h.stopped {
+		h.mu.Unlock()
+		cancel()
+		return nil
+	}
+	h.cancel, h.exited, h.gate = cancel, exited, nil
+	h.goid = goroutineID()
+	h.mu.Unlock()
+
+	defer func() {
+		cancel()
+		close(exited)
+	}()
+	if h.recover {
+		defer func() {
+			if r := recover(); r != nil {
+				err = newPanicError(r)
+			}
+		}()
+	}
+	return h.w.Run(ctx)
+}
+
+// stopForRestart cancel running worker for restart after gate is closed,
+// returns channel which is closed when worker is exited or nil if worker isn't running
+func (h *Handle) stopForRestart(gate chan struct{}) <-chan struct{} {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.status != StatusRunning || h.stopped || h.exited == nil {
+		return nil
+	}
+	h.gate = gate
+	h.cancel()
+	return h.exited
+}
+
+// restartGate returns gate of restart with other worker or nil
+func (h *Handle) restartGate() chan struct{} {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.gate
+}
+
+// runningInfo returns time of Stop call and goroutine identifiers of running job runs,
+// goroutine identifier of worker Run if there are no running runs
+func (h *Handle) runningInfo() (time.Time, []uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.runs) == 0 {
+		return h.stopAt, []uint64{h.goid}
+	}
+	ids := make([]uint64, 0, len(h.runs))
+	for id := range h.runs {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return h.stopAt, ids
+}
+
+// startRun record goroutine of job run, returns func which removes it when run is completed.
+// Runs can be started in own goroutines by worker concurrency policy or TriggerNow
+func (h *Handle) startRun() (done func()) {
+	id := goroutineID()
+	h.mu.Lock()
+	if h.runs == nil {
+		h.runs = make(map[uint64]int)
+	}
+	h.runs[id]++
+	h.mu.Unlock()
+
+	return func() {
+		h.mu.Lock()
+		if h.runs[id]--; h.runs[id] == 0 {
+			delete(h.runs, id)
+		}
+		h.mu.Unlock()
+	}
+}
+
+// isStopped returns true if worker is stopped by Stop
+func (h *Handle) isStopped() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.stopped
+}
+
+// listen run triggered runs until returned stop func is called
+func (h *Handle) listen(ctx context.Context, run func(context.Context)) (stop func()) {
+	trigger := make(chan struct{}, 1)
+	h.mu.Lock()
+	h.trigger = trigger
+	h.mu.Unlock()
+
+	quit, done := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-quit:
+				return
+			case <-ctx.Done():
+				return
+			case <-trigger:
+				run(withScheduled(ctx, clockFrom(ctx).Now()))
+			}
+		}
+	}()
+
+	return func() {
+		h.mu.Lock()
+		h.trigger = nil
+		h.mu.Unlock()
+		close(quit)
+		<-done
+	}
+}
+
+// handleFrom returns handle of worker from context or nil,
+// handle of other worker is ignored, e.g. worker runned by job of another worker
+func handleFrom(ctx context.Context, w *Worker) *Handle {
+	if h, ok := ctx.Value(handleKey{}).(*Handle); ok && h.w == w {
+		return h
+	}
+	return nil
+}
+
+// paused returns true if worker is paused by handle from context
+func paused(ctx context.Context, w *Worker) bool {
+	h := handleFrom(ctx, w)
+	return h != nil && h.IsPaused()
+}
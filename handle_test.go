@@ -0,0 +1,216 @@
+package workers_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/jenchik/workers"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestHandlePause(t *testing.T) {
+	Convey("Given worker by 1s ticker added to group", t, func() {
+		start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		res := make(chan time.Time)
+		clock := workers.NewManualClock(start)
+		wrk := workers.New(createWriterJob(res)).ByTicker(time.Second)
+
+		wg := workers.NewGroup(context.Background()).WithClock(clock)
+		Reset(func() {
+			wg.Stop()
+			wg.Wait(nil)
+		})
+		handles, err := wg.Add(nil, wrk)
+		So(err, ShouldBeNil)
+		So(handles, ShouldHaveLength, 2)
+		So(handles[0], ShouldBeNil)
+		h := handles[1]
+		So(h.Worker(), ShouldEqual, wrk)
+		wg.Run()
+
+		Convey("When worker is paused", func() {
+			h.Pause()
+			So(h.IsPaused(), ShouldBeTrue)
+
+			Convey("ticks should be skipped until worker is resumed", func() {
+				for i := 0; i < 2; i++ {
+					clock.BlockUntil(1)
+					clock.Add(time.Second)
+				}
+				clock.BlockUntil(1)
+				_, ok := readTimeWithTimeout(res, 10*time.Millisecond)
+				So(ok, ShouldBeFalse)
+				So(wrk.Stats(), ShouldResemble, workers.Stats{Skipped: 2})
+
+				h.Resume()
+				So(h.IsPaused(), ShouldBeFalse)
+				clock.Add(time.Second)
+
+				r, ok := readTimeWithTimeout(res, time.Second)
+				So(ok, ShouldBeTrue)
+				So(r, ShouldEqual, start.Add(3*time.Second))
+				So(wrk.Stats().Runs, ShouldEqual, 1)
+			})
+		})
+	})
+}
+
+func TestHandlePauseImmediately(t *testing.T) {
+	Convey("Given paused worker which runs immediately", t, func() {
+		start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		res := make(chan time.Time)
+		clock := workers.NewManualClock(start)
+		wrk := workers.New(createWriterJob(res)).SetImmediately(true).ByTicker(time.Second)
+
+		wg := workers.NewGroup(context.Background()).WithClock(clock)
+		Reset(func() {
+			wg.Stop()
+			wg.Wait(nil)
+		})
+		handles, err := wg.Add(wrk)
+		So(err, ShouldBeNil)
+		handles[0].Pause()
+		wg.Run()
+
+		Convey("immediate run should be skipped", func() {
+			clock.BlockUntil(1)
+			_, ok := readTimeWithTimeout(res, 10*time.Millisecond)
+			So(ok, ShouldBeFalse)
+			So(wrk.Stats(), ShouldResemble, workers.Stats{Skipped: 1})
+		})
+	})
+}
+
+func TestHandleControl(t *testing.T) {
+	Convey("Given group with two hourly workers", t, func() {
+		res1, res2 := make(chan time.Time), make(chan time.Time)
+		clock := workers.NewManualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+		wg := workers.NewGroup(context.Background()).WithClock(clock)
+		Reset(func() {
+			wg.Stop()
+			wg.Wait(nil)
+		})
+
+		handles, err := wg.Add(
+			workers.New(createWriterJob(res1)).ByTicker(time.Hour),
+			workers.New(createWriterJob(res2)).ByTicker(time.Hour),
+		)
+		So(err, ShouldBeNil)
+		h1, h2 := handles[0], handles[1]
+		So(h1.Status(), ShouldEqual, workers.StatusPending)
+		So(h1.TriggerNow(), ShouldBeFalse)
+
+		wg.Run()
+		clock.BlockUntil(2)
+		So(h1.Status(), ShouldEqual, workers.StatusRunning)
+
+		Convey("TriggerNow should run job out of schedule", func() {
+			So(h1.TriggerNow(), ShouldBeTrue)
+			r, ok := readTimeWithTimeout(res1, time.Second)
+			So(ok, ShouldBeTrue)
+			So(r, ShouldEqual, clock.Now())
+
+			h1.Pause()
+			So(h1.Status(), ShouldEqual, workers.StatusPaused)
+			So(h1.TriggerNow(), ShouldBeTrue)
+			_, ok = readTimeWithTimeout(res1, time.Second)
+			So(ok, ShouldBeTrue)
+		})
+
+		Convey("Stop should stop only one worker", func() {
+			h1.Stop()
+			select {
+			case <-h1.Done():
+			case <-time.After(time.Second):
+				So("worker is not stopped", ShouldBeEmpty)
+			}
+			So(h1.Wait(context.Background()), ShouldBeNil)
+			So(h1.Status(), ShouldEqual, workers.StatusStopped)
+			So(h1.TriggerNow(), ShouldBeFalse)
+
+			So(h2.Status(), ShouldEqual, workers.StatusRunning)
+			So(h2.TriggerNow(), ShouldBeTrue)
+			_, ok := readTimeWithTimeout(res2, time.Second)
+			So(ok, ShouldBeTrue)
+		})
+	})
+
+	Convey("Given group with finite workers", t, func() {
+		wg := workers.NewGroup(context.Background())
+		Reset(func() {
+			wg.Stop()
+			wg.Wait(nil)
+		})
+
+		failure := errors.New("failure")
+		started := make(chan struct{}, 1)
+		handles, err := wg.Add(
+			workers.NewE(func(context.Context) error { return failure }),
+			workers.New(func(context.Context) {}),
+			workers.New(func(context.Context) { started <- struct{}{} }),
+		)
+		So(err, ShouldBeNil)
+		handles[2].Stop()
+		wg.Run()
+
+		Convey("Wait should return status and error of finished worker", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			So(handles[0].Wait(ctx), ShouldEqual, failure)
+			So(handles[0].Status(), ShouldEqual, workers.StatusFailed)
+			So(handles[1].Wait(ctx), ShouldBeNil)
+			So(handles[1].Status(), ShouldEqual, workers.StatusCompleted)
+			So(handles[2].Wait(ctx), ShouldBeNil)
+			So(handles[2].Status(), ShouldEqual, workers.StatusStopped)
+			So(handles[2].Status().String(), ShouldEqual, "stopped")
+			So(started, ShouldBeEmpty)
+		})
+	})
+}
+
+func TestHandleRemove(t *testing.T) {
+	Convey("Given runned supervised group", t, func() {
+		wg := workers.NewGroup(context.Background()).WithSupervisor(workers.Supervisor{})
+		Reset(func() {
+			wg.Stop()
+			wg.Wait(nil)
+		})
+		wg.Run()
+
+		Convey("stopped workers should be released by group", func() {
+			const n = 10
+			collected := make(chan struct{}, n)
+			for i := 0; i < n; i++ {
+				wrk := workers.New(func(ctx context.Context) { <-ctx.Done() })
+				runtime.SetFinalizer(wrk, func(*workers.Worker) { collected <- struct{}{} })
+				handles, err := wg.Add(wrk)
+				So(err, ShouldBeNil)
+				handles[0].Stop()
+				So(handles[0].Wait(nil), ShouldBeNil)
+			}
+			// last added workers can be still referenced by stack of group loop
+			for i := 0; i < 2; i++ {
+				handles, err := wg.Add(workers.New(func(ctx context.Context) { <-ctx.Done() }))
+				So(err, ShouldBeNil)
+				handles[0].Stop()
+			}
+
+			deadline := time.After(2 * time.Second)
+			for i := 0; i < n; {
+				runtime.GC()
+				select {
+				case <-collected:
+					i++
+				case <-time.After(10 * time.Millisecond):
+				case <-deadline:
+					So("workers are not released", ShouldBeEmpty)
+					return
+				}
+			}
+		})
+	})
+}
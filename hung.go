@@ -0,0 +1,133 @@
+package workers
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// HungWorker describes worker which is still running when Group.Wait timed out
+	HungWorker struct {
+		// Name is worker name
+		Name string
+		// SinceCancel is time since worker or group cancellation, zero if worker was not canceled
+		SinceCancel time.Duration
+		// Stacks is goroutine stacks of running job runs of worker, runs can be started
+		// in own goroutines by concurrency policy or Handle.TriggerNow.
+		// Stack of worker Run is captured if there are no running runs.
+		// Stacks are captured if enabled by Group.WithHungStacks
+		Stacks []string
+	}
+
+	// HungWorkersError is returned by Group.Wait when context is done before workers are finished,
+	// it wraps context error
+	HungWorkersError struct {
+		Err     error
+		Workers []HungWorker
+	}
+)
+
+// WithHungStacks set capture of goroutine stacks of workers which are still running
+// when Group.Wait timed out, see HungWorkersError
+func (g *Group) WithHungStacks(enabled bool) *Group {
+	g.stacks = enabled
+	return g
+}
+
+func (e *HungWorkersError) Error() string {
+	workers := make([]string, 0, len(e.Workers))
+	for _, w := range e.Workers {
+		if w.SinceCancel > 0 {
+			workers = append(workers, fmt.Sprintf("%q (canceled %s ago)", w.Name, w.SinceCancel))
+		} else {
+			workers = append(workers, fmt.Sprintf("%q (not canceled)", w.Name))
+		}
+	}
+	return fmt.Sprintf("%v: %d workers still running: %s", e.Err, len(e.Workers), strings.Join(workers, ", "))
+}
+
+func (e *HungWorkersError) Unwrap() error {
+	return e.Err
+}
+
+// hung returns HungWorkersError with running workers or err if there are no running workers
+func (g *Group) hung(err error) error {
+	now := time.Now()
+	g.mu.Lock()
+	handles := g.handles
+	canceled := g.canceled
+	g.mu.Unlock()
+
+	var stacks map[uint64]string
+	if g.stacks {
+		stacks = goroutineStacks()
+	}
+
+	var hung []HungWorker
+	for _, h := range handles {
+		if s := h.Status(); s != StatusRunning && s != StatusPaused {
+			continue
+		}
+		w := HungWorker{Name: h.w.name}
+		since, ids := h.runningInfo()
+		if since.IsZero() || !canceled.IsZero() && canceled.Before(since) {
+			since = canceled
+		}
+		if !since.IsZero() {
+			w.SinceCancel = now.Sub(since)
+		}
+		for _, id := range ids {
+			if stack, ok := stacks[id]; ok {
+				w.Stacks = append(w.Stacks, stack)
+			}
+		}
+		hung = append(hung, w)
+	}
+
+	if len(hung) == 0 {
+		return err
+	}
+	return &HungWorkersError{Err: err, Workers: hung}
+}
+
+// goroutineID returns identifier of current goroutine parsed from its stack
+func goroutineID() uint64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	return parseGoroutineID(buf)
+}
+
+// goroutineStacks returns stacks of all goroutines by identifier
+func goroutineStacks() map[uint64]string {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	stacks := make(map[uint64]string)
+	for _, stack := range bytes.Split(buf, []byte("\n\n")) {
+		if id := parseGoroutineID(stack); id != 0 {
+			stacks[id] = string(stack)
+		}
+	}
+	return stacks
+}
+
+// parseGoroutineID returns identifier from stack header "goroutine 1 [running]:"
+func parseGoroutineID(stack []byte) uint64 {
+	stack = bytes.TrimPrefix(stack, []byte("goroutine "))
+	if i := bytes.IndexByte(stack, ' '); i > 0 {
+		id, _ := strconv.ParseUint(string(stack[:i]), 10, 64)
+		return id
+	}
+	return 0
+}
@@ -0,0 +1,101 @@
+package workers_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jenchik/workers"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestHungWorkers(t *testing.T) {
+	Convey("Given group with worker which ignores context", t, func() {
+		started, release := make(chan struct{}), make(chan struct{})
+		Reset(func() { close(release) })
+
+		wg := workers.NewGroup(context.Background()).WithHungStacks(true)
+		_, err := wg.Add(
+			workers.New(func(ctx context.Context) { <-ctx.Done() }).Named("ok"),
+			workers.New(func(context.Context) {
+				close(started)
+				<-release
+			}).Named("stuck"),
+		)
+		So(err, ShouldBeNil)
+		wg.Run()
+
+		Convey("When group is stopped and Wait timed out", func() {
+			<-started
+			wg.Stop()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			err := wg.Wait(ctx)
+
+			Convey("Wait should return still running worker", func() {
+				So(errors.Is(err, context.DeadlineExceeded), ShouldBeTrue)
+
+				var hung *workers.HungWorkersError
+				So(errors.As(err, &hung), ShouldBeTrue)
+				So(hung.Workers, ShouldHaveLength, 1)
+				So(hung.Workers[0].Name, ShouldEqual, "stuck")
+				So(hung.Workers[0].SinceCancel, ShouldBeGreaterThanOrEqualTo, 50*time.Millisecond)
+				So(hung.Workers[0].Stacks, ShouldHaveLength, 1)
+				So(hung.Workers[0].Stacks[0], ShouldContainSubstring, "workers_test.TestHungWorkers")
+				So(err.Error(), ShouldContainSubstring, `"stuck" (canceled`)
+			})
+		})
+	})
+
+	Convey("Given group with scheduled worker which runs concurrently and ignores context", t, func() {
+		release := make(chan struct{})
+		Reset(func() { close(release) })
+		clock := workers.NewManualClock(time.Now())
+
+		var started sync.WaitGroup
+		started.Add(2)
+		wg := workers.NewGroup(context.Background()).WithHungStacks(true)
+		handles, err := wg.Add(workers.New(func(context.Context) {
+			started.Done()
+			stuckRun(release)
+		}).
+			Named("stuck").
+			ByTicker(time.Second).
+			WithConcurrency(workers.ConcurrencyAllow).
+			WithClock(clock))
+		So(err, ShouldBeNil)
+		wg.Run()
+
+		Convey("When runs are started by schedule and trigger and Wait timed out", func() {
+			clock.BlockUntil(1)
+			clock.Add(time.Second)
+			for !handles[0].TriggerNow() {
+				time.Sleep(time.Millisecond)
+			}
+			started.Wait()
+			wg.Stop()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			err := wg.Wait(ctx)
+
+			Convey("Wait should return stacks of all running runs", func() {
+				var hung *workers.HungWorkersError
+				So(errors.As(err, &hung), ShouldBeTrue)
+				So(hung.Workers, ShouldHaveLength, 1)
+				So(hung.Workers[0].Stacks, ShouldHaveLength, 2)
+				for _, stack := range hung.Workers[0].Stacks {
+					So(stack, ShouldContainSubstring, "workers_test.stuckRun")
+				}
+			})
+		})
+	})
+}
+
+// stuckRun blocks job run until release, it's marker of job in goroutine stacks
+func stuckRun(release chan struct{}) {
+	<-release
+}
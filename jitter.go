@@ -0,0 +1,95 @@
+package workers
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"time"
+)
+
+type (
+	// Jitter returns delay of run scheduled at time t, interval is time until next scheduled run.
+	// Delay should be deterministic by t, so schedule with jitter returns same run times
+	Jitter func(t time.Time, interval time.Duration) time.Duration
+
+	// jitteredSchedule delays each run time of schedule by jitters
+	jitteredSchedule struct {
+		s      Schedule
+		jitter []Jitter
+	}
+)
+
+// MaxJitter returns jitter with pseudo-random delay up to max of each run,
+// delays are seeded randomly, so delays are different for each jitter
+func MaxJitter(max time.Duration) Jitter {
+	seed := rand.Uint64()
+	return func(t time.Time, _ time.Duration) time.Duration {
+		return randDuration(seed^uint64(t.UnixNano()), max)
+	}
+}
+
+// PercentJitter returns jitter with pseudo-random delay up to percent of interval
+// until next run, e.g. PercentJitter(10) delays runs of minute ticker up to 6s
+func PercentJitter(percent float64) Jitter {
+	seed := rand.Uint64()
+	return func(t time.Time, interval time.Duration) time.Duration {
+		return randDuration(seed^uint64(t.UnixNano()), time.Duration(float64(interval)*percent/100))
+	}
+}
+
+// HashJitter returns jitter with same delay up to max of each run, delay is derived
+// from hashed key, e.g. pod name, so each instance spreads runs but remains predictable
+func HashJitter(key string, max time.Duration) Jitter {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	seed := h.Sum64()
+	return func(time.Time, time.Duration) time.Duration {
+		return randDuration(seed, max)
+	}
+}
+
+// Jittered returns schedule with each run time delayed by sum of jitters delays,
+// delay is always less than interval until next run, so runs are never reordered
+func Jittered(s Schedule, jitter ...Jitter) Schedule {
+	if len(jitter) == 0 {
+		return s
+	}
+	return jitteredSchedule{s, jitter}
+}
+
+// Next returns delayed first run time of schedule after t
+func (s jitteredSchedule) Next(t time.Time) time.Time {
+	next := s.s.Next(t)
+	if next.IsZero() {
+		return next
+	}
+
+	var interval time.Duration
+	if after := s.s.Next(next); !after.IsZero() {
+		interval = after.Sub(next)
+	}
+
+	var delay time.Duration
+	for _, j := range s.jitter {
+		delay += j(next, interval)
+	}
+	if interval > 0 && delay >= interval {
+		delay = interval - time.Nanosecond
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	return next.Add(delay)
+}
+
+// randDuration returns pseudo-random duration in [0, max) by seed
+func randDuration(seed uint64, max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	// splitmix64
+	seed += 0x9e3779b97f4a7c15
+	seed = (seed ^ seed>>30) * 0xbf58476d1ce4e5b9
+	seed = (seed ^ seed>>27) * 0x94d049bb133111eb
+	seed ^= seed >> 31
+	return time.Duration(seed % uint64(max))
+}
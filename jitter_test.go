@@ -0,0 +1,91 @@
+package workers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jenchik/workers"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestJitter(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	offsets := func(s workers.Schedule, period time.Duration) []time.Duration {
+		var res []time.Duration
+		for i, r := range workers.Preview(s, start, 10) {
+			res = append(res, r.Sub(start.Add(time.Duration(i+1)*period)))
+		}
+		return res
+	}
+
+	Convey("Given minute schedule with jitter", t, func() {
+		Convey("MaxJitter should delay runs up to max", func() {
+			s := workers.Jittered(workers.Every(time.Minute), workers.MaxJitter(10*time.Second))
+			for _, d := range offsets(s, time.Minute) {
+				So(d, ShouldBeBetweenOrEqual, 0, 10*time.Second)
+			}
+			So(workers.Preview(s, start, 10), ShouldResemble, workers.Preview(s, start, 10))
+		})
+
+		Convey("PercentJitter should delay runs up to percent of interval", func() {
+			s := workers.Jittered(workers.Every(time.Minute), workers.PercentJitter(10))
+			for _, d := range offsets(s, time.Minute) {
+				So(d, ShouldBeBetweenOrEqual, 0, 6*time.Second)
+			}
+		})
+
+		Convey("HashJitter should delay runs by same offset for same key", func() {
+			s := workers.Jittered(workers.Every(time.Minute), workers.HashJitter("pod-1", time.Minute))
+			res := offsets(s, time.Minute)
+			So(res[0], ShouldBeBetweenOrEqual, 0, time.Minute)
+			for _, d := range res {
+				So(d, ShouldEqual, res[0])
+			}
+			So(offsets(workers.Jittered(workers.Every(time.Minute), workers.HashJitter("pod-1", time.Minute)), time.Minute), ShouldResemble, res)
+			So(offsets(workers.Jittered(workers.Every(time.Minute), workers.HashJitter("pod-2", time.Minute)), time.Minute), ShouldNotResemble, res)
+		})
+
+		Convey("jitter should not exceed interval until next run", func() {
+			s := workers.Jittered(workers.Every(time.Minute), workers.HashJitter("pod-1", time.Hour))
+			for _, d := range offsets(s, time.Minute) {
+				So(d, ShouldBeBetween, -time.Nanosecond, time.Minute)
+			}
+		})
+
+		Convey("cron schedule with jitter should delay runs", func() {
+			s, err := workers.ParseCron("0 * * * * *", workers.CronLocation(time.UTC), workers.CronJitter(workers.MaxJitter(10*time.Second)))
+			So(err, ShouldBeNil)
+			for _, d := range offsets(s, time.Minute) {
+				So(d, ShouldBeBetweenOrEqual, 0, 10*time.Second)
+			}
+		})
+	})
+
+	Convey("Given worker by ticker with hash jitter and manual clock", t, func() {
+		res := make(chan time.Time)
+		jitter := workers.HashJitter("pod-1", 10*time.Second)
+		offset := jitter(start, time.Minute)
+
+		clock := workers.NewManualClock(start)
+		wrk := workers.
+			New(createWriterJob(res)).
+			ByTicker(time.Minute, jitter).
+			WithClock(clock)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		Reset(cancel)
+		go wrk.Run(ctx)
+
+		Convey("job should be executed every minute delayed by offset", func() {
+			for i := 1; i <= 3; i++ {
+				clock.BlockUntil(1)
+				clock.Set(start.Add(time.Duration(i)*time.Minute + offset))
+
+				r, ok := readTimeWithTimeout(res, time.Second)
+				So(ok, ShouldBeTrue)
+				So(r, ShouldEqual, start.Add(time.Duration(i)*time.Minute+offset))
+			}
+		})
+	})
+}
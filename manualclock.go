@@ -0,0 +1,199 @@
+package workers
+
+import (
+	"sync"
+	"time"
+)
+
+type (
+	// ManualClock is Clock which time is changed only manually,
+	// timers and tickers are fired when time is moved forward
+	ManualClock struct {
+		mu     sync.Mutex
+		cond   *sync.Cond
+		now    time.Time
+		timers []*manualTimer
+	}
+
+	manualTimer struct {
+		clock  *ManualClock
+		c      chan time.Time
+		when   time.Time
+		period time.Duration
+		active bool
+	}
+
+	manualTicker struct {
+		*manualTimer
+	}
+)
+
+// NewManualClock returns manual clock with current time now
+func NewManualClock(now time.Time) *ManualClock {
+	c := &ManualClock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now returns current clock time
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTimer returns timer fired when clock moved forward by duration
+func (c *ManualClock) NewTimer(d time.Duration) Timer {
+	return c.timer(d, 0)
+}
+
+// NewTicker returns ticker fired each time clock moved forward by period
+func (c *ManualClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for ManualClock.NewTicker")
+	}
+	return manualTicker{c.timer(d, d)}
+}
+
+// After returns channel which receive time when clock moved forward by duration
+func (c *ManualClock) After(d time.Duration) <-chan time.Time {
+	return c.timer(d, 0).c
+}
+
+// Add moves clock forward by duration and fire all timers in order of their time
+func (c *ManualClock) Add(d time.Duration) {
+	c.Set(c.Now().Add(d))
+}
+
+// Set moves clock to time and fire all timers in order of their time,
+// clock never moves backward
+func (c *ManualClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for {
+		next := c.next(t)
+		if next == nil {
+			break
+		}
+		c.now = next.when
+		next.fire()
+	}
+	if t.After(c.now) {
+		c.now = t
+	}
+	c.cond.Broadcast()
+}
+
+// BlockUntil blocks until count of active timers and tickers is at least n,
+// allows to wait until schedule is waiting next run
+func (c *ManualClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for c.active() < n {
+		c.cond.Wait()
+	}
+}
+
+func (c *ManualClock) timer(d, period time.Duration) *manualTimer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &manualTimer{
+		clock:  c,
+		c:      make(chan time.Time, 1),
+		period: period,
+	}
+	c.timers = append(c.timers, t)
+	t.start(d)
+	return t
+}
+
+// next returns earliest active timer with time before t
+func (c *ManualClock) next(t time.Time) *manualTimer {
+	var next *manualTimer
+	for _, timer := range c.timers {
+		if !timer.active || timer.when.After(t) {
+			continue
+		}
+		if next == nil || timer.when.Before(next.when) {
+			next = timer
+		}
+	}
+	return next
+}
+
+func (c *ManualClock) active() (n int) {
+	for _, t := range c.timers {
+		if t.active {
+			n++
+		}
+	}
+	return
+}
+
+// start must be called with clock lock
+func (t *manualTimer) start(d time.Duration) {
+	t.when = t.clock.now.Add(d)
+	t.active = true
+	if d <= 0 {
+		t.fire()
+	}
+	t.clock.cond.Broadcast()
+}
+
+// fire must be called with clock lock, event is dropped if channel is full
+func (t *manualTimer) fire() {
+	select {
+	case t.c <- t.when:
+	default:
+	}
+	if t.period > 0 {
+		t.when = t.when.Add(t.period)
+		return
+	}
+	t.active = false
+	t.remove()
+}
+
+// remove inactive timer from clock, must be called with clock lock
+func (t *manualTimer) remove() {
+	timers := t.clock.timers
+	for i, timer := range timers {
+		if timer == t {
+			t.clock.timers = append(timers[:i], timers[i+1:]...)
+			return
+		}
+	}
+}
+
+func (t *manualTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *manualTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	active := t.active
+	t.active = false
+	t.remove()
+	return active
+}
+
+func (t *manualTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	active := t.active
+	if !active {
+		t.clock.timers = append(t.clock.timers, t)
+	}
+	t.start(d)
+	return active
+}
+
+func (t manualTicker) Stop() {
+	t.manualTimer.Stop()
+}
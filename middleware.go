@@ -0,0 +1,39 @@
+package workers
+
+import (
+	"context"
+)
+
+// Middleware is job wrapper for extend job execution,
+// error of wrapped run is available by RunError
+type Middleware func(Job) Job
+
+// Use append middlewares to each job run pipeline, first middleware is outermost.
+// Order of run pipeline from outermost is recover, middlewares, timeout, lock, retry and job,
+// so middlewares are called once per scheduled run and see final run error
+func (w *Worker) Use(middlewares ...Middleware) *Worker {
+	w.middlewares = append(w.middlewares, middlewares...)
+	return w
+}
+
+// UseLoop append middlewares to whole worker run, first middleware is outermost.
+// Loop middlewares are called once per Run and wrap immediately and all scheduled runs
+func (w *Worker) UseLoop(middlewares ...Middleware) *Worker {
+	w.loopMiddlewares = append(w.loopMiddlewares, middlewares...)
+	return w
+}
+
+// RunError returns error of job run from run context,
+// middleware can check result of run after wrapped job call
+func RunError(ctx context.Context) error {
+	if r, ok := ctx.Value(resultKey{}).(*result); ok {
+		return r.err
+	}
+	return nil
+}
+
+// SetRunError set error of job run in run context,
+// middleware can fail run or reset error of wrapped job call
+func SetRunError(ctx context.Context, err error) {
+	setResult(ctx, err)
+}
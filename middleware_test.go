@@ -0,0 +1,75 @@
+package workers_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jenchik/workers"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMiddleware(t *testing.T) {
+	Convey("Given failing job scheduled 2 times and tracing middlewares", t, func() {
+		var (
+			errJob = errors.New("job failed")
+			trace  []string
+		)
+		job := func(ctx context.Context) error {
+			trace = append(trace, "job")
+			return errJob
+		}
+		schedule := func(ctx context.Context, j workers.Job) workers.Job {
+			return func(ctx context.Context) {
+				j(ctx)
+				j(ctx)
+			}
+		}
+		middleware := func(name string) workers.Middleware {
+			return func(next workers.Job) workers.Job {
+				return func(ctx context.Context) {
+					trace = append(trace, name+" before")
+					next(ctx)
+					if workers.RunError(ctx) == errJob {
+						trace = append(trace, name+" error")
+					}
+					trace = append(trace, name+" after")
+				}
+			}
+		}
+
+		Convey("When run worker with run and loop middlewares", func() {
+			workers.NewE(job).
+				BySchedule(schedule).
+				Use(middleware("first"), middleware("second")).
+				UseLoop(middleware("loop")).
+				Run(context.Background())
+
+			Convey("middlewares should be called in defined order", func() {
+				run := []string{
+					"first before", "second before", "job",
+					"second error", "second after", "first error", "first after",
+				}
+				expected := append([]string{"loop before"}, run...)
+				expected = append(expected, run...)
+				expected = append(expected, "loop after")
+				So(trace, ShouldResemble, expected)
+			})
+		})
+
+		Convey("When middleware reset run error", func() {
+			err := workers.NewE(job).
+				Use(func(next workers.Job) workers.Job {
+					return func(ctx context.Context) {
+						next(ctx)
+						workers.SetRunError(ctx, nil)
+					}
+				}).
+				Run(context.Background())
+
+			Convey("run should be successful", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+}
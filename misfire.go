@@ -0,0 +1,254 @@
+package workers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MisfirePolicy is handling mode of schedule runs which were missed
+// because process was down or machine was suspended
+type MisfirePolicy int
+
+const (
+	// MisfireSkip skip missed runs and wait next run by schedule
+	MisfireSkip MisfirePolicy = iota
+	// MisfireRunOnce run job once for all missed runs
+	MisfireRunOnce
+	// MisfireRunAll run job for each missed run up to limit
+	MisfireRunAll
+)
+
+// DefaultMisfireThreshold is used when misfire threshold is not set
+const DefaultMisfireThreshold = time.Minute
+
+type (
+	// Misfire describes handling of missed schedule runs, like anacron.
+	// Runs are missed after downtime if last run time is persisted by store
+	// or when timer fired later than threshold, e.g. after suspend
+	Misfire struct {
+		// Policy is handling mode of missed runs
+		Policy MisfirePolicy
+		// Limit is max count of missed runs executed by MisfireRunAll, zero is no limit
+		Limit int
+		// Threshold is max delay of run after schedule time which is not missed,
+		// DefaultMisfireThreshold if not positive
+		Threshold time.Duration
+		// Store persists last run time by worker name, missed runs after downtime
+		// are not detected without store
+		Store LastRunStore
+	}
+
+	// LastRunStore persists last run time of workers by worker name
+	LastRunStore interface {
+		// LastRun returns last run time of worker or zero time if worker was never runned
+		LastRun(name string) (time.Time, error)
+		// SaveLastRun persists last run time of worker
+		SaveLastRun(name string, t time.Time) error
+	}
+
+	// MemoryStore is LastRunStore in memory, it's useful for tests
+	MemoryStore struct {
+		mu   sync.Mutex
+		runs map[string]time.Time
+	}
+
+	// FileStore is LastRunStore persisted to JSON file
+	FileStore struct {
+		mu   sync.Mutex
+		path string
+	}
+
+	misfireKey struct{}
+
+	// misfire is misfire handling of worker in context of schedule
+	misfire struct {
+		Misfire
+		w *Worker
+	}
+)
+
+// errMisfireName is configuration error of unnamed worker with misfire store,
+// last run times of unnamed workers are mixed in store
+var errMisfireName = errors.New("misfire store requires named worker")
+
+// WithMisfire set handling of missed schedule runs, worker name is key of last run time in store.
+// Worker with store should be named, otherwise worker has configuration error, see Err
+func (w *Worker) WithMisfire(m Misfire) *Worker {
+	w.misfire = &m
+	return w
+}
+
+// withMisfire returns context with misfire handling of worker
+func withMisfire(ctx context.Context, w *Worker) context.Context {
+	return context.WithValue(ctx, misfireKey{}, &misfire{*w.misfire, w})
+}
+
+// misfireFrom returns misfire handling from context or nil
+func misfireFrom(ctx context.Context) *misfire {
+	m, _ := ctx.Value(misfireKey{}).(*misfire)
+	return m
+}
+
+// lastRun returns persisted last run time, store error is passed to error handlers
+func (m *misfire) lastRun(ctx context.Context) time.Time {
+	if m.Store == nil {
+		return time.Time{}
+	}
+	t, err := m.Store.LastRun(m.w.name)
+	if err != nil {
+		m.w.handleError(ctx, err)
+	}
+	return t
+}
+
+// run job at schedule time and persist last run time,
+// returns false if run was skipped by worker
+func (m *misfire) run(ctx context.Context, j Job, t time.Time) bool {
+	ran := runAt(ctx, j, t)
+	if m.Store == nil {
+		return ran
+	}
+	if err := m.Store.SaveLastRun(m.w.name, t); err != nil {
+		m.w.handleError(ctx, err)
+	}
+	return ran
+}
+
+// missed returns true if run was delayed after schedule time more than threshold
+func (m *misfire) missed(scheduled, now time.Time) bool {
+	threshold := m.Threshold
+	if threshold <= 0 {
+		threshold = DefaultMisfireThreshold
+	}
+	return now.Sub(scheduled) > threshold
+}
+
+// catchUp handle missed runs of schedule after time until now by policy,
+// returns time of last handled run
+func (m *misfire) catchUp(ctx context.Context, j Job, s Schedule, after, now time.Time) time.Time {
+	var (
+		due    []time.Time
+		missed int
+	)
+	last := after
+	for i := 0; i < composeLimit; i++ {
+		t := s.Next(last)
+		if t.IsZero() || t.After(now) {
+			break
+		}
+		if m.Policy == MisfireRunAll && (m.Limit <= 0 || len(due) < m.Limit) {
+			due = append(due, t)
+		}
+		last = t
+		missed++
+	}
+	if m.Policy == MisfireRunOnce && missed > 0 {
+		// latest missed run is executed for all missed runs
+		due = append(due, last)
+	}
+	atomic.AddUint64(&m.w.stats.Skipped, uint64(missed-len(due)))
+
+	for _, t := range due {
+		select {
+		case <-ctx.Done():
+			return last
+		default:
+		}
+		tick(s, t, m.run(ctx, j, t))
+	}
+	return last
+}
+
+// NewMemoryStore returns new LastRunStore in memory
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{runs: make(map[string]time.Time)}
+}
+
+// LastRun returns last run time of worker
+func (s *MemoryStore) LastRun(name string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.runs[name], nil
+}
+
+// SaveLastRun store last run time of worker
+func (s *MemoryStore) SaveLastRun(name string, t time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runs[name] = t
+	return nil
+}
+
+// NewFileStore returns new LastRunStore persisted to JSON file by path,
+// file is created on first save
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// LastRun returns last run time of worker from file
+func (s *FileStore) LastRun(name string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	runs, err := s.read()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return runs[name], nil
+}
+
+// SaveLastRun write last run time of worker to file, file is replaced atomically
+func (s *FileStore) SaveLastRun(name string, t time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	runs, err := s.read()
+	if err != nil {
+		return err
+	}
+	runs[name] = t
+
+	data, err := json.Marshal(runs)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(data); err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), s.path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+// read returns last run times from file, missing file has no runs
+func (s *FileStore) read() (map[string]time.Time, error) {
+	runs := make(map[string]time.Time)
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return runs, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return runs, nil
+	}
+	return runs, json.Unmarshal(data, &runs)
+}
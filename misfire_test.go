@@ -0,0 +1,177 @@
+package workers_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jenchik/workers"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMisfire(t *testing.T) {
+	at := func(h int) time.Time {
+		return time.Date(2024, 1, 1, h, 0, 0, 0, time.UTC)
+	}
+
+	Convey("Given hourly worker with last run 5 hours ago", t, func() {
+		res := make(chan time.Time)
+		clock := workers.NewManualClock(at(10).Add(30 * time.Minute))
+		store := workers.NewMemoryStore()
+		So(store.SaveLastRun("report", at(5)), ShouldBeNil)
+
+		run := func(m workers.Misfire) (*workers.Worker, func()) {
+			m.Store = store
+			wrk := workers.
+				New(createWriterJob(res)).
+				Named("report").
+				WithSchedule(workers.Every(time.Hour)).
+				WithMisfire(m).
+				WithClock(clock)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				wrk.Run(ctx)
+			}()
+			return wrk, func() {
+				cancel()
+				<-done
+			}
+		}
+
+		Convey("When run with run all policy and limit", func() {
+			wrk, stop := run(workers.Misfire{Policy: workers.MisfireRunAll, Limit: 3})
+
+			Convey("earliest missed runs should be executed", func() {
+				for h := 6; h <= 8; h++ {
+					r, ok := readTimeWithTimeout(res, time.Second)
+					So(ok, ShouldBeTrue)
+					So(r, ShouldEqual, at(h))
+				}
+				clock.BlockUntil(1)
+				stop()
+
+				last, err := store.LastRun("report")
+				So(err, ShouldBeNil)
+				So(last, ShouldEqual, at(8))
+				So(wrk.Stats(), ShouldResemble, workers.Stats{Runs: 3, Skipped: 2})
+			})
+		})
+
+		Convey("When run with run once policy", func() {
+			wrk, stop := run(workers.Misfire{Policy: workers.MisfireRunOnce})
+
+			Convey("latest missed run should be executed once", func() {
+				r, ok := readTimeWithTimeout(res, time.Second)
+				So(ok, ShouldBeTrue)
+				So(r, ShouldEqual, at(10))
+
+				clock.BlockUntil(1)
+				clock.Add(30 * time.Minute)
+				r, ok = readTimeWithTimeout(res, time.Second)
+				So(ok, ShouldBeTrue)
+				So(r, ShouldEqual, at(11))
+
+				clock.BlockUntil(1)
+				stop()
+				So(wrk.Stats(), ShouldResemble, workers.Stats{Runs: 2, Skipped: 4})
+			})
+		})
+
+		Convey("When run with skip policy", func() {
+			wrk, stop := run(workers.Misfire{Policy: workers.MisfireSkip})
+
+			Convey("missed runs should be skipped", func() {
+				clock.BlockUntil(1)
+				clock.Add(30 * time.Minute)
+				r, ok := readTimeWithTimeout(res, time.Second)
+				So(ok, ShouldBeTrue)
+				So(r, ShouldEqual, at(11))
+
+				clock.BlockUntil(1)
+				stop()
+				So(wrk.Stats(), ShouldResemble, workers.Stats{Runs: 1, Skipped: 5})
+			})
+		})
+	})
+
+	Convey("Given hourly worker with run once policy without store", t, func() {
+		res := make(chan time.Time)
+		clock := workers.NewManualClock(at(10).Add(30 * time.Minute))
+		wrk := workers.
+			New(createWriterJob(res)).
+			WithSchedule(workers.Every(time.Hour)).
+			WithMisfire(workers.Misfire{Policy: workers.MisfireRunOnce}).
+			WithClock(clock)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		Reset(cancel)
+		go wrk.Run(ctx)
+
+		Convey("When timer fired late after suspend", func() {
+			clock.BlockUntil(1)
+			clock.Set(at(13).Add(30 * time.Minute))
+
+			Convey("latest missed run should be executed once", func() {
+				r, ok := readTimeWithTimeout(res, time.Second)
+				So(ok, ShouldBeTrue)
+				So(r, ShouldEqual, at(13))
+
+				clock.BlockUntil(1)
+				clock.Set(at(14))
+				r, ok = readTimeWithTimeout(res, time.Second)
+				So(ok, ShouldBeTrue)
+				So(r, ShouldEqual, at(14))
+			})
+		})
+	})
+
+	Convey("Given unnamed worker with misfire store", t, func() {
+		wrk := workers.
+			New(func(context.Context) {}).
+			WithSchedule(workers.Every(time.Hour)).
+			WithMisfire(workers.Misfire{Policy: workers.MisfireRunOnce, Store: workers.NewMemoryStore()})
+
+		Convey("worker should have configuration error", func() {
+			So(wrk.Err(), ShouldNotBeNil)
+			So(wrk.Run(context.Background()), ShouldEqual, wrk.Err())
+
+			wg := workers.NewGroup(context.Background())
+			defer wg.Stop()
+			_, err := wg.Add(wrk)
+			So(err, ShouldEqual, wrk.Err())
+		})
+
+		Convey("worker named after misfire setting should not have error", func() {
+			So(wrk.Named("report").Err(), ShouldBeNil)
+		})
+	})
+
+	Convey("Given file store", t, func() {
+		dir, err := os.MkdirTemp("", "workers")
+		So(err, ShouldBeNil)
+		Reset(func() { os.RemoveAll(dir) })
+		path := filepath.Join(dir, "runs.json")
+
+		Convey("last run times should be persisted by worker name", func() {
+			last, err := workers.NewFileStore(path).LastRun("a")
+			So(err, ShouldBeNil)
+			So(last.IsZero(), ShouldBeTrue)
+
+			So(workers.NewFileStore(path).SaveLastRun("a", at(1)), ShouldBeNil)
+			So(workers.NewFileStore(path).SaveLastRun("b", at(2)), ShouldBeNil)
+
+			store := workers.NewFileStore(path)
+			last, err = store.LastRun("a")
+			So(err, ShouldBeNil)
+			So(last.Equal(at(1)), ShouldBeTrue)
+			last, err = store.LastRun("b")
+			So(err, ShouldBeNil)
+			So(last.Equal(at(2)), ShouldBeTrue)
+		})
+	})
+}
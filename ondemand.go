@@ -10,6 +10,9 @@ func (d *onDemand) Run() error {
 	if d.w.job == nil {
 		return nil
 	}
+	if err := d.w.Err(); err != nil {
+		return err
+	}
 	select {
 	case d.g.add <- d.w.RunOnce:
 	case <-d.g.done:
@@ -0,0 +1,64 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+type (
+	// PanicError is error of job run which was panicked
+	PanicError struct {
+		// Worker is panicked worker, nil if panic was recovered outside of worker
+		Worker *Worker
+		// Value is recovered panic value
+		Value interface{}
+		// Stack is goroutine stack trace captured on recover
+		Stack []byte
+	}
+
+	// RecoverHandler is callback for panics recovered from job runs
+	RecoverHandler func(context.Context, *PanicError)
+)
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("job panic: %v", e.Value)
+}
+
+// newPanicError returns panic error for recovered value with current stack trace,
+// recovered panic error is reused for keep original stack trace
+func newPanicError(recovered interface{}) *PanicError {
+	if e, ok := recovered.(*PanicError); ok {
+		return e
+	}
+	return &PanicError{
+		Value: recovered,
+		Stack: debug.Stack(),
+	}
+}
+
+// WithRecover returns job wrapper func which recovers panic of each job run,
+// pass it to handler and set run result to panic error
+func WithRecover(h RecoverHandler) func(Job) Job {
+	return withRecover(nil, h)
+}
+
+func withRecover(w *Worker, h RecoverHandler) func(Job) Job {
+	return func(j Job) Job {
+		return func(ctx context.Context) {
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					e := newPanicError(recovered)
+					if e.Worker == nil {
+						e.Worker = w
+					}
+					if h != nil {
+						h(ctx, e)
+					}
+					setResult(ctx, e)
+				}
+			}()
+			j(ctx)
+		}
+	}
+}
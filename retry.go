@@ -0,0 +1,152 @@
+package workers
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// BackoffJitter is randomization mode of delay between retry attempts
+type BackoffJitter int
+
+const (
+	// NoJitter use exact exponential delay
+	NoJitter BackoffJitter = iota
+	// FullJitter use random delay between zero and exponential delay
+	FullJitter
+	// EqualJitter use half of exponential delay plus random delay up to other half
+	EqualJitter
+)
+
+// DefaultRetryMultiplier is used when retry policy multiplier is not set
+const DefaultRetryMultiplier = 2
+
+// DefaultRetryInterval is used when retry policy initial interval is not set
+// and attempts are not limited, so failed job is not retried in busy loop
+const DefaultRetryInterval = time.Second
+
+// RetryPolicy describes how failed job run (error or panic) is executed again.
+// Zero MaxAttempts and MaxElapsedTime means retry until run context is done.
+type RetryPolicy struct {
+	// MaxAttempts is limit of job executions per run including first one
+	MaxAttempts int
+	// InitialInterval is delay before second attempt,
+	// DefaultRetryInterval if not positive and MaxAttempts is zero
+	InitialInterval time.Duration
+	// MaxInterval is upper limit of delay between attempts, zero is no limit
+	MaxInterval time.Duration
+	// Multiplier of delay for each next attempt, DefaultRetryMultiplier if not positive
+	Multiplier float64
+	// Jitter randomization mode of delay
+	Jitter BackoffJitter
+	// MaxElapsedTime limits total duration of run with all attempts, zero is no limit
+	MaxElapsedTime time.Duration
+}
+
+// WithRetry returns job wrapper func which execute job again while run
+// fails by policy. Attempts are executed sequentially inside single run,
+// so schedule waits retries and next run never overlaps retry sequence.
+// Panic of last attempt is thrown again as *PanicError with original stack trace.
+func WithRetry(p RetryPolicy) func(Job) Job {
+	return func(j Job) Job {
+		return func(ctx context.Context) {
+			clock := clockFrom(ctx)
+			start := clock.Now()
+			for attempt := 1; ; attempt++ {
+				recovered, panicked, err := tryRun(withAttempt(ctx, attempt), j)
+				if err == nil && !panicked {
+					return
+				}
+
+				delay := p.delay(attempt)
+				if !p.allow(attempt, clock.Now().Sub(start), delay) || !sleep(ctx, clock, delay) {
+					if panicked {
+						panic(recovered)
+					}
+					setResult(ctx, err)
+					return
+				}
+			}
+		}
+	}
+}
+
+// tryRun execute job attempt, returns attempt error or recovered panic value
+func tryRun(ctx context.Context, j Job) (recovered interface{}, panicked bool, err error) {
+	r := new(result)
+	defer func() {
+		if recovered = recover(); recovered != nil {
+			recovered, panicked = newPanicError(recovered), true
+		}
+	}()
+	j(context.WithValue(ctx, resultKey{}, r))
+	return nil, false, r.err
+}
+
+// allow returns true if next attempt is allowed after delay
+func (p RetryPolicy) allow(attempt int, elapsed, delay time.Duration) bool {
+	if p.MaxAttempts > 0 && attempt >= p.MaxAttempts {
+		return false
+	}
+	if p.MaxElapsedTime > 0 && elapsed+delay > p.MaxElapsedTime {
+		return false
+	}
+	return true
+}
+
+// delay returns duration before next attempt after attempt with number
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier <= 0 {
+		multiplier = DefaultRetryMultiplier
+	}
+
+	initial := p.InitialInterval
+	if initial <= 0 && p.MaxAttempts <= 0 {
+		initial = DefaultRetryInterval
+	}
+
+	d := float64(initial) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxInterval > 0 && d > float64(p.MaxInterval) {
+		d = float64(p.MaxInterval)
+	}
+	if d > math.MaxInt64 {
+		d = math.MaxInt64
+	}
+	delay := time.Duration(d)
+	if delay <= 0 {
+		return 0
+	}
+
+	switch p.Jitter {
+	case FullJitter:
+		delay = time.Duration(rand.Int63n(int64(delay) + 1))
+	case EqualJitter:
+		half := delay / 2
+		delay = half + time.Duration(rand.Int63n(int64(delay-half)+1))
+	}
+	return delay
+}
+
+// sleep returns false if context was done before delay elapsed
+func sleep(ctx context.Context, clock Clock, delay time.Duration) bool {
+	if delay <= 0 {
+		select {
+		case <-ctx.Done():
+			return false
+		default:
+			return true
+		}
+	}
+
+	timer := clock.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C():
+		return true
+	}
+}
@@ -0,0 +1,124 @@
+package workers_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jenchik/workers"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWithRetry(t *testing.T) {
+	Convey("Given job which fails 2 times", t, func() {
+		var (
+			i      int32
+			errJob = errors.New("job failed")
+		)
+		job := func(ctx context.Context) error {
+			if atomic.AddInt32(&i, 1) <= 2 {
+				return errJob
+			}
+			return nil
+		}
+
+		Convey("When run worker with 3 attempts retry policy", func() {
+			err := workers.NewE(job).
+				WithRetry(workers.RetryPolicy{
+					MaxAttempts:     3,
+					InitialInterval: time.Millisecond,
+					Jitter:          workers.FullJitter,
+				}).
+				Run(context.Background())
+
+			Convey("run should be successful on third attempt", func() {
+				So(err, ShouldBeNil)
+				So(atomic.LoadInt32(&i), ShouldEqual, 3)
+			})
+		})
+
+		Convey("When run worker with 2 attempts retry policy", func() {
+			err := workers.NewE(job).
+				WithRetry(workers.RetryPolicy{
+					MaxAttempts:     2,
+					InitialInterval: time.Millisecond,
+				}).
+				Run(context.Background())
+
+			Convey("run should return error of last attempt", func() {
+				So(err, ShouldEqual, errJob)
+				So(atomic.LoadInt32(&i), ShouldEqual, 2)
+			})
+		})
+
+		Convey("When run worker with max elapsed time less than delay", func() {
+			err := workers.NewE(job).
+				WithRetry(workers.RetryPolicy{
+					InitialInterval: time.Minute,
+					MaxElapsedTime:  time.Second,
+				}).
+				Run(context.Background())
+
+			Convey("run should not be retried", func() {
+				So(err, ShouldEqual, errJob)
+				So(atomic.LoadInt32(&i), ShouldEqual, 1)
+			})
+		})
+
+		Convey("When context canceled while waiting next attempt", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(100*time.Millisecond, cancel)
+			err := workers.NewE(job).
+				WithRetry(workers.RetryPolicy{InitialInterval: time.Minute}).
+				Run(ctx)
+
+			Convey("run should be completed with error", func() {
+				So(err, ShouldEqual, errJob)
+				So(atomic.LoadInt32(&i), ShouldEqual, 1)
+			})
+		})
+
+		Convey("When run worker with zero retry policy until context is done", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			err := workers.NewE(job).
+				WithRetry(workers.RetryPolicy{}).
+				Run(ctx)
+
+			Convey("next attempt should wait default interval", func() {
+				So(err, ShouldEqual, errJob)
+				So(atomic.LoadInt32(&i), ShouldEqual, 1)
+			})
+		})
+	})
+
+	Convey("Given job which panics", t, func() {
+		var i int32
+		job := func(ctx context.Context) {
+			atomic.AddInt32(&i, 1)
+			panic("test")
+		}
+
+		Convey("Panic of last attempt should be thrown", func() {
+			wrk := workers.New(job).WithRetry(workers.RetryPolicy{MaxAttempts: 3})
+			So(func() { wrk.Run(context.Background()) }, ShouldPanic)
+			So(atomic.LoadInt32(&i), ShouldEqual, 3)
+		})
+
+		Convey("Panic of last attempt should be recovered with original value", func() {
+			var recovered *workers.PanicError
+			err := workers.New(job).
+				WithRetry(workers.RetryPolicy{MaxAttempts: 2}).
+				WithRecover(func(ctx context.Context, p *workers.PanicError) {
+					recovered = p
+				}).
+				Run(context.Background())
+
+			So(err, ShouldEqual, recovered)
+			So(recovered.Value, ShouldEqual, "test")
+			So(atomic.LoadInt32(&i), ShouldEqual, 2)
+		})
+	})
+}
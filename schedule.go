@@ -2,79 +2,206 @@ package workers
 
 import (
 	"context"
+	"sync/atomic"
 	"time"
+)
+
+type (
+	// ScheduleFunc is job wrapper for implement job run schedule
+	ScheduleFunc func(context.Context, Job) Job
+
+	// Schedule describes job run times, Next returns first run time after t
+	// or zero time if there are no more runs
+	Schedule interface {
+		Next(t time.Time) time.Time
+	}
+
+	// NextFunc is custom Schedule func
+	NextFunc func(t time.Time) time.Time
+
+	// DelaySchedule runs job each period after previous run completed
+	DelaySchedule struct {
+		Period time.Duration
+	}
 
-	"github.com/robfig/cron"
+	// EverySchedule runs job each period since origin,
+	// zero origin means runs at multiples of period since zero time
+	EverySchedule struct {
+		Period time.Duration
+		Origin time.Time
+	}
 )
 
-// ScheduleFunc is job wrapper for implement job run schedule
-type ScheduleFunc func(context.Context, Job) Job
+// Next returns result of f call
+func (f NextFunc) Next(t time.Time) time.Time {
+	return f(t)
+}
 
-// ByTimer returns job wrapper func for run job each period duration
-// after previous run completed
-func ByTimer(period time.Duration) ScheduleFunc {
+// Delay returns schedule with run each period after previous run completed
+func Delay(period time.Duration) DelaySchedule {
+	return DelaySchedule{Period: period}
+}
+
+// Next returns t plus period
+func (s DelaySchedule) Next(t time.Time) time.Time {
+	return t.Add(s.Period)
+}
+
+// Every returns schedule with run at multiples of period since zero time,
+// e.g. every 5 minutes at :00, :05, :10 and so on
+func Every(period time.Duration) EverySchedule {
+	return EverySchedule{Period: period}
+}
+
+// Next returns first multiple of period since origin after t
+func (s EverySchedule) Next(t time.Time) time.Time {
+	if s.Period <= 0 {
+		return time.Time{}
+	}
+	if s.Origin.IsZero() {
+		return t.Truncate(s.Period).Add(s.Period)
+	}
+	if t.Before(s.Origin) {
+		return s.Origin
+	}
+	return s.Origin.Add((t.Sub(s.Origin)/s.Period + 1) * s.Period)
+}
+
+// ParseCron returns schedule by cron spec or error if spec not valid
+func ParseCron(spec string, opts ...CronOption) (Schedule, error) {
+	s, err := parseCron(spec, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return Jittered(s, s.jitter...), nil
+}
+
+// Preview returns up to n next run times of schedule started at t
+func Preview(s Schedule, t time.Time, n int) []time.Time {
+	s = bind(s, t)
+	times := make([]time.Time, 0, n)
+	for len(times) < n {
+		t = s.Next(t)
+		if t.IsZero() {
+			break
+		}
+		times = append(times, t)
+		tick(s, t, true)
+	}
+	return times
+}
+
+// BySchedule returns job wrapper func for run job by schedule,
+// next run time is calculated when previous run completed,
+// timers are created by clock from context.
+// Job wrapper is completed when schedule has no more runs
+func BySchedule(s Schedule) ScheduleFunc {
 	return func(ctx context.Context, j Job) Job {
 		return func(ctx context.Context) {
-			timer := time.NewTimer(period)
-			defer timer.Stop()
-
-			for {
-				select {
-				case <-ctx.Done():
-					return
-				case <-timer.C:
-					j(ctx)
-					timer.Reset(period)
-				}
-			}
+			runSchedule(ctx, j, s)
 		}
 	}
 }
 
-// ByTicker returns func which run Worker by ticker each period duration
-func ByTicker(period time.Duration) ScheduleFunc {
+// ByTimer returns job wrapper func for run job each period duration
+// after previous run completed, timer is created by clock from context.
+// Runs are delayed by optional jitters
+func ByTimer(period time.Duration, jitter ...Jitter) ScheduleFunc {
+	return BySchedule(Jittered(Delay(period), jitter...))
+}
+
+// ByTicker returns func which run Worker by ticker each period duration,
+// ticks are skipped while job is running, ticker is created by clock from context.
+// Ticks are delayed by optional jitters, e.g. for spread load of many instances
+func ByTicker(period time.Duration, jitter ...Jitter) ScheduleFunc {
 	return func(ctx context.Context, j Job) Job {
 		return func(ctx context.Context) {
-			ticker := time.NewTicker(period)
-			defer ticker.Stop()
-
-			for {
-				select {
-				case <-ctx.Done():
-					return
-				case <-ticker.C:
-					j(ctx)
-				}
-			}
+			runSchedule(ctx, j, Jittered(EverySchedule{
+				Period: period,
+				Origin: clockFrom(ctx).Now(),
+			}, jitter...))
 		}
 	}
 }
 
 // ByCronSchedule returns job wrapper func for run job by cron schedule
-// using robfig/cron parser for parse cron spec.
-// If schedule spec not valid throw panic, shit happens.
-func ByCronSchedule(schedule string) ScheduleFunc {
-	s, err := cron.Parse(schedule)
+// with cron spec parsed by CronDefault mode or mode from options, time is checked by clock from context.
+// If schedule spec not valid throw panic, shit happens, use ParseCronSchedule for handle error.
+func ByCronSchedule(schedule string, opts ...CronOption) ScheduleFunc {
+	s, err := ParseCronSchedule(schedule, opts...)
 	if err != nil {
 		panic("parse cron spec fatal error: " + err.Error())
 	}
+	return s
+}
 
-	return func(ctx context.Context, job Job) Job {
-		return func(ctx context.Context) {
-			now := time.Now()
-			timer := time.NewTimer(s.Next(now).Sub(now))
-			defer timer.Stop()
-
-			for {
-				select {
-				case <-ctx.Done():
-					return
-				case <-timer.C:
-					job(ctx)
-					now = time.Now()
-					timer.Reset(s.Next(now).Sub(now))
-				}
+// ParseCronSchedule returns job wrapper func for run job by cron schedule
+// or error if schedule spec not valid.
+// Spec can be prefixed by time zone, e.g. "CRON_TZ=Europe/Berlin 0 0 3 * * *"
+func ParseCronSchedule(schedule string, opts ...CronOption) (ScheduleFunc, error) {
+	s, err := ParseCron(schedule, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return BySchedule(s), nil
+}
+
+// runSchedule run job at each schedule time until context is done or schedule has no runs.
+// Missed runs are handled by misfire policy from context
+func runSchedule(ctx context.Context, j Job, s Schedule) {
+	clock := clockFrom(ctx)
+	m := misfireFrom(ctx)
+
+	now := clock.Now()
+	s = bind(s, now)
+	if m != nil {
+		// catch up runs missed while process was down
+		if last := m.lastRun(ctx); !last.IsZero() && last.Before(now) {
+			if last = m.catchUp(ctx, j, s, last, now); now.Before(last) {
+				now = last
 			}
 		}
 	}
+
+	next := s.Next(now)
+	if next.IsZero() {
+		return
+	}
+
+	timer := clock.NewTimer(next.Sub(clock.Now()))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C():
+			switch now := clock.Now(); {
+			case m == nil:
+				tick(s, next, runAt(ctx, j, next))
+			case m.missed(next, now):
+				// timer fired late, e.g. after suspend
+				next = m.catchUp(ctx, j, s, next.Add(-time.Nanosecond), now)
+			default:
+				tick(s, next, m.run(ctx, j, next))
+			}
+
+			// never run twice at same time if clock was moved back
+			now := clock.Now()
+			if now.Before(next) {
+				now = next
+			}
+			if next = s.Next(now); next.IsZero() {
+				return
+			}
+			timer.Reset(next.Sub(clock.Now()))
+		}
+	}
+}
+
+// runAt run job at schedule time, returns false if run was skipped by worker, e.g. paused
+func runAt(ctx context.Context, j Job, t time.Time) bool {
+	var skipped int32
+	j(withScheduled(context.WithValue(ctx, skippedKey{}, &skipped), t))
+	return atomic.LoadInt32(&skipped) == 0
 }
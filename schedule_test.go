@@ -44,32 +44,45 @@ func TestByCustomSchedule(t *testing.T) {
 
 func TestByTimer(t *testing.T) {
 
-	Convey("Given job who send to result channel execution time and sleep for 1s", t, func() {
-		res := make(chan time.Time)
-		job := createWriterJob(time.Second, res)
+	Convey("Given job who send to result channel scheduled time and wait for ack", t, func() {
+		var (
+			res = make(chan time.Time)
+			ack = make(chan struct{})
+		)
+		job := func(ctx context.Context) {
+			info, _ := workers.RunInfoFrom(ctx)
+			select {
+			case res <- info.Scheduled:
+				<-ack
+			case <-ctx.Done():
+			}
+		}
 
-		Convey("When create worker and run with 1s timer", func() {
+		Convey("When create worker and run with 1s timer by manual clock", func() {
+			clock := workers.NewManualClock(time.Now())
 			wrk := workers.
 				New(job).
-				ByTimer(time.Second)
+				ByTimer(time.Second).
+				WithClock(clock)
 
 			ctx, cancel := context.WithCancel(context.Background())
+			Reset(cancel)
 			go wrk.Run(ctx)
-			expectedNextExecutionTime := time.Now().Add(time.Second)
-
-			Convey("job should be executed after 1s from previous run", func() {
-				timer := time.NewTimer(2 * time.Second)
-				defer timer.Stop()
+			expectedNextExecutionTime := clock.Now().Add(time.Second)
 
+			Convey("job should be executed after 1s from previous run completed", func() {
 				for i := 0; i < 3; i++ {
-					select {
-					case r := <-res:
-						So(int64(expectedNextExecutionTime.Sub(r).Seconds()), ShouldEqual, 0)
-						expectedNextExecutionTime = r.Add(time.Second)
-						timer.Reset(2 * time.Second)
-					case <-timer.C:
-						So(false, ShouldBeTrue)
-					}
+					clock.BlockUntil(1)
+					clock.Add(time.Second)
+
+					r, ok := readTimeWithTimeout(res, time.Second)
+					So(ok, ShouldBeTrue)
+					So(r, ShouldEqual, expectedNextExecutionTime)
+
+					// job run takes 500ms
+					clock.Add(500 * time.Millisecond)
+					ack <- struct{}{}
+					expectedNextExecutionTime = r.Add(1500 * time.Millisecond)
 				}
 			})
 
@@ -77,15 +90,9 @@ func TestByTimer(t *testing.T) {
 				cancel()
 
 				Convey("job execution should be stopped", func() {
-					timer := time.NewTimer(2 * time.Second)
-					defer timer.Stop()
-
-					select {
-					case <-res:
-						So(false, ShouldBeTrue)
-					case <-timer.C:
-						So(true, ShouldBeTrue)
-					}
+					clock.Add(2 * time.Second)
+					_, ok := readTimeWithTimeout(res, 100*time.Millisecond)
+					So(ok, ShouldBeFalse)
 				})
 			})
 		})
@@ -94,32 +101,31 @@ func TestByTimer(t *testing.T) {
 
 func TestByTicker(t *testing.T) {
 
-	Convey("Given job who send to result channel execution time and sleep for 1s", t, func() {
+	Convey("Given job who send to result channel scheduled time", t, func() {
 		res := make(chan time.Time)
-		job := createWriterJob(time.Second, res)
+		job := createWriterJob(res)
 
-		Convey("When create worker and run with 1s ticker", func() {
+		Convey("When create worker and run with 1s ticker by manual clock", func() {
+			clock := workers.NewManualClock(time.Now())
 			wrk := workers.
 				New(job).
-				ByTicker(time.Second)
+				ByTicker(time.Second).
+				WithClock(clock)
 
 			ctx, cancel := context.WithCancel(context.Background())
+			Reset(cancel)
 			go wrk.Run(ctx)
-			expectedNextExecutionTime := time.Now().Add(time.Second)
+			expectedNextExecutionTime := clock.Now().Add(time.Second)
 
 			Convey("job should be executed every 1s", func() {
-				timer := time.NewTimer(2 * time.Second)
-				defer timer.Stop()
-
 				for i := 0; i < 3; i++ {
-					select {
-					case r := <-res:
-						So(int64(expectedNextExecutionTime.Sub(r).Seconds()), ShouldEqual, 0)
-						expectedNextExecutionTime = r.Add(time.Second)
-						timer.Reset(2 * time.Second)
-					case <-timer.C:
-						So(false, ShouldBeTrue)
-					}
+					clock.BlockUntil(1)
+					clock.Add(time.Second)
+
+					r, ok := readTimeWithTimeout(res, time.Second)
+					So(ok, ShouldBeTrue)
+					So(r, ShouldEqual, expectedNextExecutionTime)
+					expectedNextExecutionTime = r.Add(time.Second)
 				}
 			})
 
@@ -127,15 +133,9 @@ func TestByTicker(t *testing.T) {
 				cancel()
 
 				Convey("job execution should be stopped", func() {
-					timer := time.NewTimer(time.Second)
-					defer timer.Stop()
-
-					select {
-					case <-res:
-						So(false, ShouldBeTrue)
-					case <-timer.C:
-						So(true, ShouldBeTrue)
-					}
+					clock.Add(2 * time.Second)
+					_, ok := readTimeWithTimeout(res, 100*time.Millisecond)
+					So(ok, ShouldBeFalse)
 				})
 			})
 		})
@@ -212,38 +212,62 @@ func TestByTicker(t *testing.T) {
 
 func TestByCronSchedule(t *testing.T) {
 
-	Convey("Given job who send to result channel execution time and sleep for 1s", t, func() {
+	Convey("Given job who send to result channel scheduled time", t, func() {
 		res := make(chan time.Time)
-		job := createWriterJob(time.Microsecond, res)
+		job := createWriterJob(res)
+
+		Convey("When create cron schedule with incorrect spec should panic", func() {
+			So(func() { workers.ByCronSchedule("завтра") }, ShouldPanic)
+			So(func() { workers.ByCronSchedule("@today") }, ShouldPanic)
+			So(func() { workers.ByCronSchedule("*") }, ShouldPanic)
+		})
 
-		Convey("When create worker with incorrect cron spec should panic", func() {
-			So(func() { workers.New(job).ByCronSpec("завтра") }, ShouldPanic)
-			So(func() { workers.New(job).ByCronSpec("@today") }, ShouldPanic)
-			So(func() { workers.New(job).ByCronSpec("*") }, ShouldPanic)
+		Convey("When parse incorrect cron spec should return error", func() {
+			for _, spec := range []string{"завтра", "@today", "*"} {
+				s, err := workers.ParseCronSchedule(spec)
+				So(s, ShouldBeNil)
+				So(err, ShouldNotBeNil)
+			}
+		})
+
+		Convey("When create worker with incorrect cron spec", func() {
+			var wrk *workers.Worker
+			So(func() { wrk = workers.New(job).ByCronSpec("завтра") }, ShouldNotPanic)
+
+			Convey("worker should have configuration error", func() {
+				So(wrk.Err(), ShouldNotBeNil)
+				So(wrk.Run(context.Background()), ShouldEqual, wrk.Err())
+			})
+
+			Convey("adding worker to group should return configuration error", func() {
+				g := workers.NewGroup(context.Background())
+				defer g.Stop()
+				_, err := g.Add(wrk)
+				So(err, ShouldEqual, wrk.Err())
+			})
 		})
 
-		Convey("When create worker and run with 1s cron schedule", func() {
+		Convey("When create worker and run with 1s cron schedule by manual clock", func() {
+			clock := workers.NewManualClock(time.Now().Truncate(time.Second))
 			wrk := workers.
 				New(job).
-				ByCronSpec("@every 1s")
+				ByCronSpec("@every 1s").
+				WithClock(clock)
 
 			ctx, cancel := context.WithCancel(context.Background())
+			Reset(cancel)
 			go wrk.Run(ctx)
-			expectedNextExecutionTime := time.Now().Add(time.Second)
+			expectedNextExecutionTime := clock.Now().Add(time.Second)
 
 			Convey("job should be executed every 1s", func() {
-				timer := time.NewTimer(2 * time.Second)
-				defer timer.Stop()
-
 				for i := 0; i < 3; i++ {
-					select {
-					case r := <-res:
-						So(int64(expectedNextExecutionTime.Sub(r).Seconds()), ShouldEqual, 0)
-						expectedNextExecutionTime = r.Add(time.Second)
-						timer.Reset(2 * time.Second)
-					case <-timer.C:
-						So(false, ShouldBeTrue)
-					}
+					clock.BlockUntil(1)
+					clock.Add(time.Second)
+
+					r, ok := readTimeWithTimeout(res, time.Second)
+					So(ok, ShouldBeTrue)
+					So(r, ShouldEqual, expectedNextExecutionTime)
+					expectedNextExecutionTime = r.Add(time.Second)
 				}
 			})
 
@@ -251,25 +275,103 @@ func TestByCronSchedule(t *testing.T) {
 				cancel()
 
 				Convey("job execution should be stopped", func() {
-					timer := time.NewTimer(time.Second)
-					defer timer.Stop()
-
-					select {
-					case <-res:
-						So(false, ShouldBeTrue)
-					case <-timer.C:
-						So(true, ShouldBeTrue)
-					}
+					clock.Add(2 * time.Second)
+					_, ok := readTimeWithTimeout(res, 100*time.Millisecond)
+					So(ok, ShouldBeFalse)
 				})
 			})
 		})
 	})
 }
-func createWriterJob(sleep time.Duration, ch chan time.Time) workers.Job {
+
+func createWriterJob(ch chan time.Time) workers.Job {
 	return func(ctx context.Context) {
+		info, _ := workers.RunInfoFrom(ctx)
 		select {
-		case ch <- time.Now():
+		case ch <- info.Scheduled:
 		case <-ctx.Done():
 		}
 	}
 }
+
+func readTimeWithTimeout(ch chan time.Time, timeout time.Duration) (time.Time, bool) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case t := <-ch:
+		return t, true
+	case <-timer.C:
+		return time.Time{}, false
+	}
+}
+
+func TestSchedulePreview(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 2, 30, 0, time.UTC)
+
+	Convey("Given schedules", t, func() {
+		Convey("Delay schedule should return times after period", func() {
+			So(workers.Preview(workers.Delay(time.Minute), start, 2), ShouldResemble, []time.Time{
+				start.Add(time.Minute),
+				start.Add(2 * time.Minute),
+			})
+		})
+
+		Convey("Every schedule should return multiples of period", func() {
+			So(workers.Preview(workers.Every(5*time.Minute), start, 2), ShouldResemble, []time.Time{
+				time.Date(2024, 1, 1, 10, 5, 0, 0, time.UTC),
+				time.Date(2024, 1, 1, 10, 10, 0, 0, time.UTC),
+			})
+
+			s := workers.EverySchedule{Period: time.Hour, Origin: start}
+			So(workers.Preview(s, start.Add(-time.Hour), 2), ShouldResemble, []time.Time{
+				start,
+				start.Add(time.Hour),
+			})
+		})
+
+		Convey("Cron schedule should return times by spec", func() {
+			s, err := workers.ParseCron("0 0 9 * * MON-FRI", workers.CronLocation(time.UTC))
+			So(err, ShouldBeNil)
+			So(workers.Preview(s, time.Date(2024, 1, 5, 12, 0, 0, 0, time.UTC), 2), ShouldResemble, []time.Time{
+				time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 9, 9, 0, 0, 0, time.UTC),
+			})
+		})
+
+		Convey("Custom schedule should be completed by zero time", func() {
+			s := workers.NextFunc(func(t time.Time) time.Time {
+				if t.Before(start) {
+					return start
+				}
+				return time.Time{}
+			})
+			So(workers.Preview(s, start.Add(-time.Hour), 3), ShouldResemble, []time.Time{start})
+		})
+	})
+
+	Convey("Given worker by custom schedule with single run", t, func() {
+		var i int32
+		clock := workers.NewManualClock(start)
+		wrk := workers.New(func(ctx context.Context) {
+			atomic.AddInt32(&i, 1)
+		}).WithSchedule(workers.NextFunc(func(t time.Time) time.Time {
+			if t.Before(start.Add(time.Second)) {
+				return start.Add(time.Second)
+			}
+			return time.Time{}
+		})).WithClock(clock)
+
+		Convey("Worker should be completed after run", func() {
+			done := make(chan struct{})
+			go func() {
+				wrk.Run(context.Background())
+				close(done)
+			}()
+			clock.BlockUntil(1)
+			clock.Add(time.Second)
+			So(readFromChannelWithTimeout(done), ShouldBeTrue)
+			So(atomic.LoadInt32(&i), ShouldEqual, 1)
+		})
+	})
+}
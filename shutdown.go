@@ -0,0 +1,116 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// StageTimeoutError is returned by Group.Shutdown when workers of stage were not stopped in time
+type StageTimeoutError struct {
+	// Stage is shutdown stage of workers
+	Stage int
+	// Timeout is stage timeout
+	Timeout time.Duration
+	// Workers is names of still running workers
+	Workers []string
+}
+
+func (e *StageTimeoutError) Error() string {
+	return fmt.Sprintf("shutdown stage %d timed out after %s, running workers: %s",
+		e.Stage, e.Timeout, strings.Join(e.Workers, ", "))
+}
+
+// WithStage set shutdown stage of worker, workers are stopped by Group.Shutdown
+// in stages order from lower to higher stage, 0 by default
+func (w *Worker) WithStage(stage int) *Worker {
+	w.stage = stage
+	return w
+}
+
+// WithStageTimeout set timeout of waiting for workers of shutdown stage.
+// Should be called before Shutdown
+func (g *Group) WithStageTimeout(stage int, timeout time.Duration) *Group {
+	if g.timeouts == nil {
+		g.timeouts = make(map[int]time.Duration)
+	}
+	g.timeouts[stage] = timeout
+	return g
+}
+
+// Shutdown stop workers by stages: workers of stage are stopped and waited
+// before next stage, e.g. ingest workers first, then flushers, then metrics exporter.
+// Workers of timed out stage keep stopping and next stage is started,
+// timed out stages are returned as *StageTimeoutError.
+// Group is stopped after all stages, returns *HungWorkersError if context is done before
+func (g *Group) Shutdown(ctx context.Context) error {
+	g.mu.Lock()
+	stages := make(map[int][]*Handle)
+	for _, h := range g.handles {
+		stages[h.w.stage] = append(stages[h.w.stage], h)
+	}
+	g.mu.Unlock()
+
+	order := make([]int, 0, len(stages))
+	for stage := range stages {
+		order = append(order, stage)
+	}
+	sort.Ints(order)
+
+	var errs []error
+	for _, stage := range order {
+		if err := g.shutdownStage(ctx, stage, stages[stage]); err != nil {
+			errs = append(errs, err)
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	g.stop()
+	if err := g.Wait(ctx); err != nil && ctx.Err() != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return errors.Join(errs...)
+}
+
+// shutdownStage stop workers of stage and wait them until stage timeout
+func (g *Group) shutdownStage(ctx context.Context, stage int, handles []*Handle) error {
+	timeout := g.timeouts[stage]
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	for _, h := range handles {
+		h.Stop()
+	}
+
+	for _, h := range handles {
+		select {
+		case <-h.Done():
+		case <-ctx.Done():
+		}
+	}
+
+	var running []string
+	for _, h := range handles {
+		select {
+		case <-h.Done():
+		default:
+			running = append(running, h.w.name)
+		}
+	}
+	if len(running) == 0 {
+		return nil
+	}
+	return &StageTimeoutError{Stage: stage, Timeout: timeout, Workers: running}
+}
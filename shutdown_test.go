@@ -0,0 +1,75 @@
+package workers_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jenchik/workers"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestShutdown(t *testing.T) {
+	Convey("Given group with workers of three shutdown stages", t, func() {
+		var (
+			mu      sync.Mutex
+			stopped []string
+			started sync.WaitGroup
+		)
+		job := func(name string, delay time.Duration) workers.Job {
+			started.Add(1)
+			return func(ctx context.Context) {
+				started.Done()
+				<-ctx.Done()
+				time.Sleep(delay)
+				mu.Lock()
+				stopped = append(stopped, name)
+				mu.Unlock()
+			}
+		}
+
+		wg := workers.NewGroup(context.Background())
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		Reset(cancel)
+
+		Convey("When shutdown group", func() {
+			_, err := wg.Add(
+				workers.New(job("metrics", 0)).WithStage(2),
+				workers.New(job("flusher", 0)).WithStage(1),
+				workers.New(job("ingest", 20*time.Millisecond)),
+			)
+			So(err, ShouldBeNil)
+			wg.Run()
+			started.Wait()
+
+			Convey("workers should be stopped by stages order", func() {
+				So(wg.Shutdown(ctx), ShouldBeNil)
+				So(stopped, ShouldResemble, []string{"ingest", "flusher", "metrics"})
+			})
+		})
+
+		Convey("When stage is not stopped in time", func() {
+			wg.WithStageTimeout(1, 20*time.Millisecond)
+			_, err := wg.Add(
+				workers.New(job("metrics", 0)).WithStage(2),
+				workers.New(job("slow", 200*time.Millisecond)).Named("slow").WithStage(1),
+				workers.New(job("flusher", 0)).Named("flusher").WithStage(1),
+			)
+			So(err, ShouldBeNil)
+			wg.Run()
+			started.Wait()
+
+			Convey("timed out stage should be reported and next stage should be stopped", func() {
+				err := wg.Shutdown(ctx)
+				var timeout *workers.StageTimeoutError
+				So(errors.As(err, &timeout), ShouldBeTrue)
+				So(timeout.Stage, ShouldEqual, 1)
+				So(timeout.Timeout, ShouldEqual, 20*time.Millisecond)
+				So(timeout.Workers, ShouldResemble, []string{"slow"})
+				So(stopped, ShouldResemble, []string{"flusher", "metrics", "slow"})
+			})
+		})
+	})
+}
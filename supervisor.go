@@ -0,0 +1,185 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RestartPolicy describes when worker finished in supervised group is restarted
+type RestartPolicy int
+
+const (
+	// Permanent worker is always restarted
+	Permanent RestartPolicy = iota
+	// Transient worker is restarted only if it's finished with error or panic
+	Transient
+	// Temporary worker is never restarted
+	Temporary
+)
+
+// SupervisorStrategy describes which workers of supervised group are restarted
+type SupervisorStrategy int
+
+const (
+	// OneForOne restart only finished worker
+	OneForOne SupervisorStrategy = iota
+	// OneForAll stop and restart all group workers
+	OneForAll
+	// RestForOne stop and restart finished worker and workers added after it
+	RestForOne
+)
+
+const (
+	// DefaultMaxRestarts is used when supervisor max restarts is not set
+	DefaultMaxRestarts = 3
+	// DefaultRestartPeriod is used when supervisor period is not set
+	DefaultRestartPeriod = 5 * time.Second
+)
+
+// ErrTooManyRestarts group is stopped because workers restart intensity was exceeded
+var ErrTooManyRestarts = errors.New("too many restarts")
+
+type (
+	// Supervisor describes restarts of group workers like Erlang/OTP supervisor.
+	// If there are more than MaxRestarts restarts within Period, group is stopped
+	// and Group.Wait returns ErrTooManyRestarts
+	Supervisor struct {
+		// Strategy of restart
+		Strategy SupervisorStrategy
+		// MaxRestarts is restart intensity limit, DefaultMaxRestarts if zero, negative is no limit
+		MaxRestarts int
+		// Period of restart intensity limit, DefaultRestartPeriod if not positive
+		Period time.Duration
+	}
+
+	// supervisor restarts workers of group
+	supervisor struct {
+		Supervisor
+		g        *Group
+		mu       sync.Mutex
+		children []*Handle
+		restarts []time.Time
+	}
+)
+
+// WithRestart set restart policy of worker in supervised group, Permanent by default
+func (w *Worker) WithRestart(p RestartPolicy) *Worker {
+	w.restart = p
+	return w
+}
+
+// WithSupervisor set supervision of group workers, panic of worker is recovered
+// and worker is restarted by its restart policy and supervisor strategy.
+// Should be called before adding workers
+func (g *Group) WithSupervisor(s Supervisor) *Group {
+	g.sup = &supervisor{Supervisor: s, g: g}
+	return g
+}
+
+// add worker handle to supervised workers
+func (s *supervisor) add(h *Handle) {
+	s.mu.Lock()
+	s.children = append(s.children, h)
+	s.mu.Unlock()
+}
+
+// remove finished worker handle from supervised workers
+func (s *supervisor) remove(h *Handle) {
+	s.mu.Lock()
+	s.children = removeHandle(s.children, h)
+	s.mu.Unlock()
+}
+
+// restart returns true if finished worker should be started again,
+// workers which are restarted with it by strategy are stopped before return
+func (s *supervisor) restart(ctx context.Context, h *Handle, err error) bool {
+	s.mu.Lock()
+	if gate := h.restartGate(); gate != nil {
+		// worker is stopped by strategy for restart with other worker
+		s.mu.Unlock()
+		select {
+		case <-gate:
+			return ctx.Err() == nil && h.w.restart != Temporary
+		case <-ctx.Done():
+			return false
+		}
+	}
+	defer s.mu.Unlock()
+
+	if ctx.Err() != nil || h.isStopped() {
+		return false
+	}
+	switch h.w.restart {
+	case Temporary:
+		return false
+	case Transient:
+		if err == nil {
+			return false
+		}
+	}
+
+	if !s.allow(clockFrom(ctx).Now()) {
+		s.g.fail(fmt.Errorf("%w: worker %q finished with %v", ErrTooManyRestarts, h.w.name, err))
+		return false
+	}
+
+	gate := make(chan struct{})
+	var stopping []<-chan struct{}
+	for _, c := range s.siblings(h) {
+		if exited := c.stopForRestart(gate); exited != nil {
+			stopping = append(stopping, exited)
+		}
+	}
+	for _, exited := range stopping {
+		select {
+		case <-exited:
+		case <-ctx.Done():
+		}
+	}
+	close(gate)
+	return ctx.Err() == nil
+}
+
+// allow returns true if restart at time now doesn't exceed restart intensity
+func (s *supervisor) allow(now time.Time) bool {
+	max, period := s.MaxRestarts, s.Period
+	if max == 0 {
+		max = DefaultMaxRestarts
+	}
+	if period <= 0 {
+		period = DefaultRestartPeriod
+	}
+
+	restarts := s.restarts[:0]
+	for _, t := range s.restarts {
+		if now.Sub(t) < period {
+			restarts = append(restarts, t)
+		}
+	}
+	s.restarts = append(restarts, now)
+	return max < 0 || len(s.restarts) <= max
+}
+
+// siblings returns workers which are restarted with worker by strategy
+func (s *supervisor) siblings(h *Handle) []*Handle {
+	switch s.Strategy {
+	case OneForAll:
+		siblings := make([]*Handle, 0, len(s.children))
+		for _, c := range s.children {
+			if c != h {
+				siblings = append(siblings, c)
+			}
+		}
+		return siblings
+	case RestForOne:
+		for i, c := range s.children {
+			if c == h {
+				return s.children[i+1:]
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package workers_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jenchik/workers"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSupervisor(t *testing.T) {
+	errJob := errors.New("job failed")
+
+	// job returns results of runs and then blocks until context is done
+	scriptedJob := func(starts *int32, results ...error) workers.JobE {
+		return func(ctx context.Context) error {
+			n := int(atomic.AddInt32(starts, 1))
+			if n <= len(results) {
+				if results[n-1] == errPanic {
+					panic("job panic")
+				}
+				return results[n-1]
+			}
+			<-ctx.Done()
+			return nil
+		}
+	}
+	eventually := func(starts *int32, expected int32) int32 {
+		deadline := time.Now().Add(time.Second)
+		for atomic.LoadInt32(starts) < expected && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		// wait for unexpected restarts
+		time.Sleep(20 * time.Millisecond)
+		return atomic.LoadInt32(starts)
+	}
+
+	Convey("Given supervised group with one for one strategy", t, func() {
+		wg := workers.NewGroup(context.Background()).WithSupervisor(workers.Supervisor{MaxRestarts: -1})
+		Reset(func() {
+			wg.Stop()
+			wg.Wait(nil)
+		})
+
+		Convey("permanent worker should be restarted after each finish", func() {
+			var starts int32
+			handles, err := wg.Add(workers.NewE(scriptedJob(&starts, nil, errJob, errPanic)))
+			So(err, ShouldBeNil)
+			wg.Run()
+
+			So(eventually(&starts, 4), ShouldEqual, 4)
+			So(handles[0].Status(), ShouldEqual, workers.StatusRunning)
+		})
+
+		Convey("transient worker should be restarted only after failure", func() {
+			var starts int32
+			handles, err := wg.Add(workers.NewE(scriptedJob(&starts, errJob, errPanic, nil)).WithRestart(workers.Transient))
+			So(err, ShouldBeNil)
+			wg.Run()
+
+			So(handles[0].Wait(context.Background()), ShouldBeNil)
+			So(handles[0].Status(), ShouldEqual, workers.StatusCompleted)
+			So(eventually(&starts, 3), ShouldEqual, 3)
+		})
+
+		Convey("temporary worker should not be restarted", func() {
+			var starts int32
+			handles, err := wg.Add(workers.NewE(scriptedJob(&starts, errJob)).WithRestart(workers.Temporary))
+			So(err, ShouldBeNil)
+			wg.Run()
+
+			So(handles[0].Wait(context.Background()), ShouldEqual, errJob)
+			So(handles[0].Status(), ShouldEqual, workers.StatusFailed)
+			So(eventually(&starts, 1), ShouldEqual, 1)
+		})
+	})
+
+	Convey("Given supervised group with restart intensity limit", t, func() {
+		wg := workers.NewGroup(context.Background()).WithSupervisor(workers.Supervisor{MaxRestarts: 2, Period: time.Minute})
+
+		var starts int32
+		_, err := wg.Add(workers.NewE(scriptedJob(&starts, errJob, errJob, errJob, errJob)))
+		So(err, ShouldBeNil)
+		wg.Run()
+
+		Convey("group should be stopped when limit is exceeded", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			err := wg.Wait(ctx)
+			So(errors.Is(err, workers.ErrTooManyRestarts), ShouldBeTrue)
+			So(atomic.LoadInt32(&starts), ShouldEqual, 3)
+		})
+	})
+
+	Convey("Given supervised group with three workers", t, func() {
+		var a, b, c int32
+		run := func(strategy workers.SupervisorStrategy) {
+			wg := workers.NewGroup(context.Background()).WithSupervisor(workers.Supervisor{Strategy: strategy})
+			Reset(func() {
+				wg.Stop()
+				wg.Wait(nil)
+			})
+			failing := scriptedJob(&b, errJob)
+			_, err := wg.Add(
+				workers.NewE(scriptedJob(&a)),
+				workers.NewE(func(ctx context.Context) error {
+					// fail after all workers are started
+					for atomic.LoadInt32(&a) == 0 || atomic.LoadInt32(&c) == 0 {
+						time.Sleep(time.Millisecond)
+					}
+					return failing(ctx)
+				}),
+				workers.NewE(scriptedJob(&c)),
+			)
+			So(err, ShouldBeNil)
+			wg.Run()
+		}
+
+		Convey("one for all strategy should restart all workers", func() {
+			run(workers.OneForAll)
+			So(eventually(&a, 2), ShouldEqual, 2)
+			So(eventually(&b, 2), ShouldEqual, 2)
+			So(eventually(&c, 2), ShouldEqual, 2)
+		})
+
+		Convey("rest for one strategy should restart workers added after failed one", func() {
+			run(workers.RestForOne)
+			So(eventually(&b, 2), ShouldEqual, 2)
+			So(eventually(&c, 2), ShouldEqual, 2)
+			So(eventually(&a, 1), ShouldEqual, 1)
+		})
+	})
+}
+
+// errPanic is result of scripted job which panics
+var errPanic = errors.New("panic")
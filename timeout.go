@@ -0,0 +1,45 @@
+package workers
+
+import (
+	"context"
+	"time"
+)
+
+// TimeoutError is error of job run which was not completed before run timeout
+type TimeoutError struct {
+	// Timeout is run duration limit
+	Timeout time.Duration
+	// Err is error returned by job
+	Err error
+}
+
+func (e *TimeoutError) Error() string {
+	return "job run timeout " + e.Timeout.String() + ": " + e.Err.Error()
+}
+
+// Unwrap returns error returned by job
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// WithTimeout returns job wrapper func which run job with deadline context.
+// Job run error is replaced by *TimeoutError if deadline was exceeded,
+// but parent context is not done, so timeout is distinct from cancellation.
+// Job should respect context for complete run by timeout
+func WithTimeout(d time.Duration) func(Job) Job {
+	return func(j Job) Job {
+		return func(ctx context.Context) {
+			runCtx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+
+			r := new(result)
+			j(context.WithValue(runCtx, resultKey{}, r))
+
+			err := r.err
+			if err != nil && runCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+				err = &TimeoutError{Timeout: d, Err: err}
+			}
+			setResult(ctx, err)
+		}
+	}
+}
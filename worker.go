@@ -2,6 +2,8 @@ package workers
 
 import (
 	"context"
+	"fmt"
+	"sync/atomic"
 	"time"
 )
 
@@ -9,44 +11,140 @@ type (
 	// Job is target background job
 	Job func(context.Context)
 
+	// JobE is target background job which can fail,
+	// error of each run is passed to worker and group error handlers
+	JobE func(context.Context) error
+
+	// ErrorHandler is callback for errors returned by job runs
+	ErrorHandler func(context.Context, error)
+
 	// Worker is builder for job with optional schedule and exclusive control
 	Worker struct {
-		job         Job
+		stats       Stats
+		err         error
+		name        string
+		labels      map[string]string
+		job         JobE
 		done        func()
+		complete    func()
+		onError     ErrorHandler
+		recover     func(Job) Job
+		retry       func(Job) Job
+		timeout     func(Job) Job
 		locker      LockFunc
+		middlewares []Middleware
 		schedule    ScheduleFunc
+		calendar    *Calendar
+		misfire     *Misfire
+		restart     RestartPolicy
+		stage       int
+		deps        []*Worker
+		concurrency ConcurrencyPolicy
+		adaptive    bool
+		clock       Clock
 		immediately bool
+
+		loopMiddlewares []Middleware
 	}
 )
 
 // New returns new worker with target job
 func New(job Job) *Worker {
+	w := &Worker{}
+	if job != nil {
+		w.job = func(ctx context.Context) error {
+			job(ctx)
+			return nil
+		}
+	}
+	return w
+}
+
+// NewE returns new worker with target job which returns error
+func NewE(job JobE) *Worker {
 	return &Worker{
 		job: job,
 	}
 }
 
+// Named set worker name, name is available in run descriptor of job context
+func (w *Worker) Named(name string) *Worker {
+	w.name = name
+	return w
+}
+
+// Name returns worker name
+func (w *Worker) Name() string {
+	return w.name
+}
+
+// WithLabel set worker label, labels are available in run descriptor of job context
+func (w *Worker) WithLabel(key, value string) *Worker {
+	labels := make(map[string]string, len(w.labels)+1)
+	for k, v := range w.labels {
+		labels[k] = v
+	}
+	labels[key] = value
+	w.labels = labels
+	return w
+}
+
+// Labels returns copy of worker labels
+func (w *Worker) Labels() map[string]string {
+	labels := make(map[string]string, len(w.labels))
+	for k, v := range w.labels {
+		labels[k] = v
+	}
+	return labels
+}
+
 // BySchedule set schedule wrapper func for job
 func (w *Worker) BySchedule(s ScheduleFunc) *Worker {
-	w.schedule = s
+	w.schedule, w.adaptive = s, false
+	return w
+}
+
+// WithSchedule set job wrapper for run job by schedule
+func (w *Worker) WithSchedule(s Schedule) *Worker {
+	w.schedule, w.adaptive = BySchedule(s), false
+	return w
+}
+
+// ByTimer set schedule timer job wrapper with period and optional jitters
+func (w *Worker) ByTimer(period time.Duration, jitter ...Jitter) *Worker {
+	w.schedule, w.adaptive = ByTimer(period, jitter...), false
 	return w
 }
 
-// ByTimer set schedule timer job wrapper with period
-func (w *Worker) ByTimer(period time.Duration) *Worker {
-	w.schedule = ByTimer(period)
+// ByTicker set schedule ticker job wrapper with period and optional jitters
+func (w *Worker) ByTicker(period time.Duration, jitter ...Jitter) *Worker {
+	w.schedule, w.adaptive = ByTicker(period, jitter...), false
 	return w
 }
 
-// ByTicker set schedule ticker job wrapper with period
-func (w *Worker) ByTicker(period time.Duration) *Worker {
-	w.schedule = ByTicker(period)
+// ByCronSpec set schedule job wrapper by cron spec with optional time zone prefix.
+// If spec not valid then worker configuration error is set, see Err
+func (w *Worker) ByCronSpec(spec string, opts ...CronOption) *Worker {
+	s, err := ParseCronSchedule(spec, opts...)
+	if err != nil {
+		w.setErr(fmt.Errorf("parse cron spec %q: %w", spec, err))
+		return w
+	}
+	w.schedule, w.adaptive = s, false
 	return w
 }
 
-// ByCronSpec set schedule job wrapper by cron spec
-func (w *Worker) ByCronSpec(spec string) *Worker {
-	w.schedule = ByCronSchedule(spec)
+// WithCalendar set blackout calendar, scheduled runs at blackout dates are skipped.
+// Use Calendar.Shift with WithSchedule for move runs to next allowed date
+func (w *Worker) WithCalendar(c *Calendar) *Worker {
+	w.calendar = c
+	return w
+}
+
+// WithConcurrency set policy for scheduled runs when previous run is still running.
+// Worker by adaptive schedule supports ConcurrencyWait only, other policy is configuration error
+func (w *Worker) WithConcurrency(p ConcurrencyPolicy) *Worker {
+	w.concurrency = p
 	return w
 }
 
@@ -62,55 +160,245 @@ func (w *Worker) WithDone(done func()) *Worker {
 	return w
 }
 
+// WithComplete set callback which is called when worker completed naturally:
+// schedule has no more runs or single run of worker without schedule is finished.
+// It isn't called if worker is stopped by context
+func (w *Worker) WithComplete(complete func()) *Worker {
+	w.complete = complete
+	return w
+}
+
+// WithErrorHandler set handler for errors returned by job runs.
+// Errors are passed to group error handler too, if worker runned by group
+func (w *Worker) WithErrorHandler(h ErrorHandler) *Worker {
+	w.onError = h
+	return w
+}
+
+// WithRecover set recovering panic of each job run, panic is passed to handler
+// with stack trace and worker, then schedule continues work.
+// Recovered panic is handled as run error too
+func (w *Worker) WithRecover(h RecoverHandler) *Worker {
+	w.recover = withRecover(w, h)
+	return w
+}
+
+// WithRetry set policy for execute failed job run again.
+// Run is completed when all attempts are done or run context is canceled
+func (w *Worker) WithRetry(p RetryPolicy) *Worker {
+	w.retry = WithRetry(p)
+	return w
+}
+
+// WithTimeout set duration limit of each job run including all retry attempts.
+// Error of timed out run is *TimeoutError
+func (w *Worker) WithTimeout(d time.Duration) *Worker {
+	w.timeout = WithTimeout(d)
+	return w
+}
+
 // WithLock set job lock wrapper
 func (w *Worker) WithLock(l Locker) *Worker {
 	w.locker = WithLock(l)
 	return w
 }
 
-// Run job, wrap job to lock and schedule wrappers
-func (w *Worker) Run(ctx context.Context) {
+// Err returns first worker configuration error, worker with error can't be runned.
+// Combination of settings is checked when worker is runned or added to group,
+// so order of builder calls doesn't matter
+func (w *Worker) Err() error {
+	if w.err != nil {
+		return w.err
+	}
+	return w.check()
+}
+
+// check returns configuration error of worker settings combination
+func (w *Worker) check() error {
+	if w.misfire != nil && w.misfire.Store != nil && w.name == "" {
+		return errMisfireName
+	}
+	if w.adaptive && w.concurrency != ConcurrencyWait {
+		return errAdaptiveConcurrency
+	}
+	return nil
+}
+
+// setErr set configuration error if worker has no error yet
+func (w *Worker) setErr(err error) {
+	if w.err == nil {
+		w.err = err
+	}
+}
+
+// Run job, wrap job to lock and schedule wrappers, whole run is wrapped to loop middlewares.
+// Returns error of job run if worker is not scheduled, fatal error of scheduled run
+// or worker configuration error
+func (w *Worker) Run(ctx context.Context) (err error) {
+	if err := w.Err(); err != nil {
+		return err
+	}
 	if w.done != nil {
 		defer w.done()
 	}
-	job := w.job
 
-	if w.locker != nil {
-		job = w.locker(ctx, job)
+	if w.clock != nil {
+		ctx = withClock(ctx, w.clock)
+	}
+	if w.misfire != nil {
+		ctx = withMisfire(ctx, w)
+	}
+
+	loop := func(ctx context.Context) {
+		err = w.loop(ctx)
+	}
+	for i := len(w.loopMiddlewares) - 1; i >= 0; i-- {
+		loop = w.loopMiddlewares[i](loop)
 	}
 
+	loop(ctx)
+	return
+}
+
+// loop run job immediately and by schedule
+func (w *Worker) loop(ctx context.Context) error {
+	job := w.wrap(ctx)
+
 	if w.immediately {
-		job(ctx)
+		var err error
+		if w.skipped(ctx) {
+			atomic.AddUint64(&w.stats.Skipped, 1)
+		} else {
+			err = w.call(ctx, job)
+		}
 
 		if w.schedule == nil {
-			return
+			w.completed(ctx)
+			return err
+		}
+
+		if isFatal(err) {
+			return err
 		}
 
 		// check context before run immediately job again
 		select {
 		case <-ctx.Done():
-			return
+			return nil
 		default:
 		}
 	}
 
-	if w.schedule != nil {
-		job = w.schedule(ctx, job)
+	if w.schedule == nil {
+		err := w.call(ctx, job)
+		w.completed(ctx)
+		return err
+	}
+
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	d := &dispatcher{w: w, stop: stop}
+	d.job = func(ctx context.Context) {
+		d.fail(w.call(ctx, job))
+	}
+	defer d.wait()
+
+	w.scheduled(ctx, d)
+	d.wait()
+	if err := d.err(); err != nil {
+		return err
 	}
+	w.completed(ctx)
+	return nil
+}
 
-	job(ctx)
+// scheduled run job by schedule, runs triggered by worker handle
+// are executed until schedule is completed
+func (w *Worker) scheduled(ctx context.Context, d *dispatcher) {
+	if h := handleFrom(ctx, w); h != nil {
+		defer h.listen(ctx, d.dispatch)()
+	}
+	w.schedule(ctx, d.run)(ctx)
+}
+
+// completed call complete callback if worker is not stopped by context
+func (w *Worker) completed(ctx context.Context) {
+	if w.complete != nil && ctx.Err() == nil {
+		w.complete()
+	}
 }
 
 // RunOnce job, wrap job to lock
-func (w *Worker) RunOnce(ctx context.Context) {
+func (w *Worker) RunOnce(ctx context.Context) error {
+	if err := w.Err(); err != nil {
+		return err
+	}
 	if w.done != nil {
 		defer w.done()
 	}
-	job := w.job
+	if w.clock != nil {
+		ctx = withClock(ctx, w.clock)
+	}
+	return w.call(ctx, w.wrap(ctx))
+}
+
+// wrap returns job for single run wrapped to recover, middlewares, timeout, lock and retry,
+// job result is stored to run result in context
+func (w *Worker) wrap(ctx context.Context) Job {
+	job := func(ctx context.Context) {
+		setResult(ctx, w.job(ctx))
+	}
+
+	if w.retry != nil {
+		job = w.retry(job)
+	}
 
 	if w.locker != nil {
 		job = w.locker(ctx, job)
 	}
 
-	job(ctx)
+	if w.timeout != nil {
+		job = w.timeout(job)
+	}
+
+	for i := len(w.middlewares) - 1; i >= 0; i-- {
+		job = w.middlewares[i](job)
+	}
+
+	if w.recover != nil {
+		job = w.recover(job)
+	}
+	return job
+}
+
+// call single job run with run descriptor and pass result error to error handlers
+func (w *Worker) call(ctx context.Context, job Job) error {
+	atomic.AddUint64(&w.stats.Runs, 1)
+	h := handleFrom(ctx, w)
+	if h != nil {
+		defer h.startRun()()
+	}
+	ctx = withRunInfo(ctx, w)
+	r := new(result)
+	job(context.WithValue(ctx, resultKey{}, r))
+	if r.err == nil {
+		if h != nil {
+			h.setReady()
+		}
+	} else {
+		SetPollResult(ctx, PollError)
+		atomic.AddUint64(&w.stats.Failed, 1)
+		w.handleError(ctx, r.err)
+	}
+	return r.err
+}
+
+func (w *Worker) handleError(ctx context.Context, err error) {
+	if w.onError != nil {
+		w.onError(ctx, err)
+	}
+	if h, ok := ctx.Value(errorHandlerKey{}).(ErrorHandler); ok && h != nil {
+		h(ctx, err)
+	}
 }
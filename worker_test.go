@@ -2,8 +2,10 @@ package workers_test
 
 import (
 	"context"
+	"errors"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/jenchik/workers"
 	. "github.com/smartystreets/goconvey/convey"
@@ -68,3 +70,179 @@ func TestImmediately(t *testing.T) {
 		})
 	})
 }
+
+func TestErrorHandler(t *testing.T) {
+	Convey("Given worker with failing job", t, func() {
+		var (
+			errJob = errors.New("job failed")
+			errs   = make(chan error, 10)
+		)
+		job := func(ctx context.Context) error {
+			return errJob
+		}
+		handler := func(ctx context.Context, err error) {
+			errs <- err
+		}
+
+		Convey("Run without schedule should return job error to caller and handler", func() {
+			err := workers.NewE(job).WithErrorHandler(handler).Run(context.Background())
+			So(err, ShouldEqual, errJob)
+			So(<-errs, ShouldEqual, errJob)
+		})
+
+		Convey("Error should be passed through lock wrapper", func() {
+			err := workers.NewE(job).
+				WithErrorHandler(handler).
+				WithLock(new(testLocker)).
+				RunOnce(context.Background())
+			So(err, ShouldEqual, errJob)
+			So(<-errs, ShouldEqual, errJob)
+		})
+
+		Convey("Error of each scheduled run should be passed to handler", func() {
+			schedule := func(ctx context.Context, j workers.Job) workers.Job {
+				return func(ctx context.Context) {
+					for i := 0; i < 3; i++ {
+						j(ctx)
+					}
+				}
+			}
+			err := workers.NewE(job).
+				WithErrorHandler(handler).
+				BySchedule(schedule).
+				Run(context.Background())
+			So(err, ShouldBeNil)
+			So(len(errs), ShouldEqual, 3)
+		})
+	})
+}
+
+type testLocker struct {
+	locked int32
+}
+
+func (l *testLocker) Lock() error {
+	if atomic.CompareAndSwapInt32(&l.locked, 0, 1) {
+		return nil
+	}
+	return errors.New("locked")
+}
+
+func (l *testLocker) Unlock() {
+	atomic.StoreInt32(&l.locked, 0)
+}
+
+func TestWithRecover(t *testing.T) {
+	Convey("Given worker with panicking job and custom schedule with 3 runs", t, func() {
+		var (
+			i      int32
+			panics = make(chan *workers.PanicError, 3)
+		)
+		schedule := func(ctx context.Context, j workers.Job) workers.Job {
+			return func(ctx context.Context) {
+				for k := 0; k < 3; k++ {
+					j(ctx)
+				}
+			}
+		}
+		wrk := workers.New(func(ctx context.Context) {
+			atomic.AddInt32(&i, 1)
+			panic("test")
+		}).BySchedule(schedule)
+
+		Convey("When run worker with recover", func() {
+			wrk.WithRecover(func(ctx context.Context, p *workers.PanicError) {
+				panics <- p
+			})
+			So(func() { wrk.Run(context.Background()) }, ShouldNotPanic)
+
+			Convey("each run should be recovered with stack trace and worker", func() {
+				So(atomic.LoadInt32(&i), ShouldEqual, 3)
+				So(len(panics), ShouldEqual, 3)
+				p := <-panics
+				So(p.Value, ShouldEqual, "test")
+				So(p.Worker, ShouldEqual, wrk)
+				So(string(p.Stack), ShouldContainSubstring, "worker_test.go")
+			})
+		})
+	})
+}
+
+func TestWithTimeout(t *testing.T) {
+	Convey("Given job which waits context done", t, func() {
+		job := func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+
+		Convey("When run worker with timeout", func() {
+			err := workers.NewE(job).
+				WithTimeout(10 * time.Millisecond).
+				Run(context.Background())
+
+			Convey("run should be completed with timeout error", func() {
+				var te *workers.TimeoutError
+				So(errors.As(err, &te), ShouldBeTrue)
+				So(te.Timeout, ShouldEqual, 10*time.Millisecond)
+				So(errors.Is(err, context.DeadlineExceeded), ShouldBeTrue)
+			})
+		})
+
+		Convey("When run worker with timeout and cancel parent context", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(10*time.Millisecond, cancel)
+			err := workers.NewE(job).
+				WithTimeout(time.Minute).
+				Run(ctx)
+
+			Convey("run error should be cancellation", func() {
+				var te *workers.TimeoutError
+				So(errors.As(err, &te), ShouldBeFalse)
+				So(err, ShouldEqual, context.Canceled)
+			})
+		})
+	})
+}
+
+func TestRunInfo(t *testing.T) {
+	Convey("Given named worker with label and job failing once", t, func() {
+		infos := make(chan workers.RunInfo, 4)
+		job := func(ctx context.Context) error {
+			info, ok := workers.RunInfoFrom(ctx)
+			So(ok, ShouldBeTrue)
+			infos <- info
+			if info.Attempt == 1 {
+				return errors.New("job failed")
+			}
+			return nil
+		}
+		wrk := workers.NewE(job).
+			Named("test").
+			WithLabel("team", "core").
+			WithRetry(workers.RetryPolicy{MaxAttempts: 2})
+
+		Convey("When run worker twice", func() {
+			So(wrk.Run(context.Background()), ShouldBeNil)
+			So(wrk.Run(context.Background()), ShouldBeNil)
+
+			Convey("job should get run descriptor for each attempt", func() {
+				So(len(infos), ShouldEqual, 4)
+				first, second := <-infos, <-infos
+				So(first.Worker, ShouldEqual, "test")
+				So(first.Labels, ShouldResemble, map[string]string{"team": "core"})
+				So(first.Attempt, ShouldEqual, 1)
+				So(second.Attempt, ShouldEqual, 2)
+				So(second.RunID, ShouldEqual, first.RunID)
+				So(second.Scheduled, ShouldEqual, first.Scheduled)
+
+				third := <-infos
+				So(third.RunID, ShouldBeGreaterThan, first.RunID)
+			})
+		})
+
+		Convey("Context without run should not contain run descriptor", func() {
+			_, ok := workers.RunInfoFrom(context.Background())
+			So(ok, ShouldBeFalse)
+		})
+	})
+}